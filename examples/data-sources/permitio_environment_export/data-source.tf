@@ -0,0 +1,7 @@
+data "permitio_environment_export" "snapshot" {}
+
+# Keep the snapshot in the repository so policy changes show up in code review.
+resource "local_file" "policy_snapshot" {
+  filename = "${path.module}/policy-snapshot.json"
+  content  = data.permitio_environment_export.snapshot.json
+}
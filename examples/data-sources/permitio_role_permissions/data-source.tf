@@ -0,0 +1,10 @@
+data "permitio_role_permissions" "folder_owner" {
+  role     = "owner"
+  resource = "folder"
+}
+
+# Every action a folder owner can perform, including those granted on the
+# documents inside the folder through role derivations.
+output "folder_owner_permissions" {
+  value = data.permitio_role_permissions.folder_owner.permissions
+}
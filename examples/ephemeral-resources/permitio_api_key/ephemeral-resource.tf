@@ -0,0 +1,10 @@
+# Mint a read-only key for the run, deleted again once Terraform is done with it.
+ephemeral "permitio_api_key" "read_only" {
+  access_level = "read"
+}
+
+# Use it for a provider that should only ever read the policy.
+provider "permitio" {
+  alias   = "read_only"
+  api_key = ephemeral.permitio_api_key.read_only.api_key
+}
@@ -0,0 +1,13 @@
+ephemeral "permitio_pdp_api_key" "pdp" {}
+
+# Hand the key to the PDP deployment without storing it in the Terraform state.
+resource "kubernetes_secret_v1" "pdp" {
+  metadata {
+    name = "permit-pdp"
+  }
+
+  data_wo = {
+    PDP_API_KEY = ephemeral.permitio_pdp_api_key.pdp.api_key
+  }
+  data_wo_revision = 1
+}
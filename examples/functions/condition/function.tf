@@ -0,0 +1,12 @@
+# Provider functions require Terraform 1.8 or later.
+resource "permitio_user_set" "privileged_users" {
+  key  = "privileged_users"
+  name = "Privileged Users"
+  conditions = provider::permitio::all_of([
+    provider::permitio::condition("subject.email", "contains", "@admin.com"),
+    provider::permitio::any_of([
+      provider::permitio::condition("subject.department", "equals", "security"),
+      provider::permitio::condition("subject.level", "greater-than", 3),
+    ]),
+  ])
+}
@@ -0,0 +1,8 @@
+# Provider functions require Terraform 1.8 or later.
+resource "permitio_role" "reader" {
+  key  = "reader"
+  name = "Reader"
+  permissions = [
+    provider::permitio::permission(permitio_resource.document.key, "read"),
+  ]
+}
@@ -0,0 +1,5 @@
+# Provider functions require Terraform 1.8 or later.
+import {
+  to = permitio_role_assignment.admin
+  id = provider::permitio::role_assignment_import_id("john@example.com", "admin", "default")
+}
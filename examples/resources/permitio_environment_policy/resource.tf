@@ -0,0 +1,10 @@
+resource "permitio_environment_policy" "production" {
+  resources         = true
+  managed_resources = [permitio_resource.document.key, permitio_resource.folder.key]
+
+  roles = true
+  managed_roles = [
+    permitio_role.admin.key,
+    "${permitio_role.document_editor.resource}:${permitio_role.document_editor.key}",
+  ]
+}
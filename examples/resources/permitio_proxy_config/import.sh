@@ -0,0 +1,3 @@
+# Import a proxy config using the format: proxy_config_key
+# auth_secret is not returned by the API and must be set in the configuration.
+terraform import permitio_proxy_config.example stripe
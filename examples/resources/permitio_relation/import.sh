@@ -0,0 +1,2 @@
+# Import a relation using the format: object_resource:relation_key
+terraform import permitio_relation.example document:parent
@@ -0,0 +1,2 @@
+# Import a resource using the format: resource_key
+terraform import permitio_resource.example document
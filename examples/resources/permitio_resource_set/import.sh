@@ -0,0 +1,2 @@
+# Import a resource set using the format: resource_set_key
+terraform import permitio_resource_set.example sensitive-docs
@@ -0,0 +1,2 @@
+# Import a role derivation using the format: resource:to_role:on_resource:role:linked_by
+terraform import permitio_role_derivation.example document:editor:folder:editor:parent
@@ -0,0 +1,2 @@
+# Import a tenant using the format: tenant_key
+terraform import permitio_tenant.example acme-corp
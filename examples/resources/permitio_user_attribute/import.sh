@@ -0,0 +1,2 @@
+# Import a user attribute using the format: attribute_key
+terraform import permitio_user_attribute.example department
@@ -0,0 +1,2 @@
+# Import the roles of a user in a tenant using the format: user:tenant
+terraform import permitio_user_roles.example john@example.com:default
@@ -0,0 +1,2 @@
+# Import a user set using the format: user_set_key
+terraform import permitio_user_set.example admins
@@ -0,0 +1,544 @@
+// Package export generates Terraform configuration for the objects that
+// already exist in a Permit environment, together with import blocks that
+// bring them under management of this provider.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/user_attributes"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const (
+	typeResource         = "permitio_resource"
+	typeRole             = "permitio_role"
+	typeRelation         = "permitio_relation"
+	typeRoleDerivation   = "permitio_role_derivation"
+	typeUserSet          = "permitio_user_set"
+	typeResourceSet      = "permitio_resource_set"
+	typeConditionSetRule = "permitio_condition_set_rule"
+	typeTenant           = "permitio_tenant"
+	typeUserAttribute    = "permitio_user_attribute"
+	typeProxyConfig      = "permitio_proxy_config"
+)
+
+// Exporter walks a Permit environment and writes it out as HCL.
+type Exporter struct {
+	client *permit.Client
+
+	file  *hclwrite.File
+	names *names
+
+	resources []common.ResourceListing
+}
+
+func New(client *permit.Client) *Exporter {
+	return &Exporter{client: client}
+}
+
+// Export writes a resource block and a matching import block for every
+// exportable object in the environment.
+func (e *Exporter) Export(ctx context.Context, w io.Writer) error {
+	e.file = hclwrite.NewEmptyFile()
+	e.names = newNames()
+
+	steps := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"resources", e.exportResources},
+		{"user attributes", e.exportUserAttributes},
+		{"roles", e.exportRoles},
+		{"relations", e.exportRelations},
+		{"role derivations", e.exportRoleDerivations},
+		{"condition sets", e.exportConditionSets},
+		{"condition set rules", e.exportConditionSetRules},
+		{"tenants", e.exportTenants},
+		{"proxy configs", e.exportProxyConfigs},
+	}
+
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			return fmt.Errorf("exporting %s: %w", step.name, err)
+		}
+	}
+
+	_, err := w.Write(e.file.Bytes())
+	return err
+}
+
+func (e *Exporter) exportResources(ctx context.Context) error {
+	var err error
+	e.resources, err = common.ListResources(ctx, e.client, common.ResourceListOptions{Roles: true, Relations: true})
+
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range e.resources {
+		name := e.names.assign(typeResource, resource.Key, resource.Key)
+		body := e.addResource(typeResource, name, resource.Key)
+
+		body.SetAttributeValue("key", cty.StringVal(resource.Key))
+		body.SetAttributeValue("name", cty.StringVal(resource.Name))
+		setOptionalString(body, "description", resource.Description)
+
+		actions := map[string]cty.Value{}
+		if resource.Actions != nil {
+			for key, action := range *resource.Actions {
+				attributes := map[string]cty.Value{"name": cty.StringVal(key)}
+				if action.Name != nil {
+					attributes["name"] = cty.StringVal(*action.Name)
+				}
+				if action.Description != nil && *action.Description != "" {
+					attributes["description"] = cty.StringVal(*action.Description)
+				}
+				actions[key] = cty.ObjectVal(attributes)
+			}
+		}
+		body.SetAttributeValue("actions", objectOrEmpty(actions))
+
+		if resource.Attributes != nil && len(*resource.Attributes) > 0 {
+			attributes := map[string]cty.Value{}
+			for key, attribute := range *resource.Attributes {
+				values := map[string]cty.Value{"type": cty.StringVal(string(attribute.Type))}
+				if attribute.Description != nil && *attribute.Description != "" {
+					values["description"] = cty.StringVal(*attribute.Description)
+				}
+				attributes[key] = cty.ObjectVal(values)
+			}
+			body.SetAttributeValue("attributes", cty.ObjectVal(attributes))
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportUserAttributes(ctx context.Context) error {
+	attributes, err := common.ListAllPages(func(page int, perPage int) ([]models.ResourceAttributeRead, error) {
+		return e.client.Api.ResourceAttributes.List(ctx, user_attributes.UserKey, page, perPage)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Key < attributes[j].Key })
+
+	for _, attribute := range attributes {
+		name := e.names.assign(typeUserAttribute, attribute.Key, attribute.Key)
+		body := e.addResource(typeUserAttribute, name, attribute.Key)
+
+		description := ""
+		if attribute.Description != nil {
+			description = *attribute.Description
+		}
+
+		body.SetAttributeValue("key", cty.StringVal(attribute.Key))
+		body.SetAttributeValue("type", cty.StringVal(string(attribute.Type)))
+		body.SetAttributeValue("description", cty.StringVal(description))
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportRoles(ctx context.Context) error {
+	roles, err := common.ListAllPages(func(page int, perPage int) ([]models.RoleRead, error) {
+		return e.client.Api.Roles.List(ctx, page, perPage)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Key < roles[j].Key })
+
+	for _, role := range roles {
+		name := e.names.assign(typeRole, role.Key, role.Key)
+		body := e.addResource(typeRole, name, role.Key)
+
+		body.SetAttributeValue("key", cty.StringVal(role.Key))
+		body.SetAttributeValue("name", cty.StringVal(role.Name))
+		setOptionalString(body, "description", role.Description)
+		body.SetAttributeValue("permissions", stringList(role.Permissions))
+		body.SetAttributeValue("extends", stringList(role.Extends))
+
+		// Tenant-level permissions are "resource:action", so the resources
+		// they refer to must exist before the role is created.
+		var dependsOn []hclwrite.Tokens
+		seen := map[string]struct{}{}
+		for _, permission := range role.Permissions {
+			resourceKey, _, _ := strings.Cut(permission, ":")
+			if _, ok := seen[resourceKey]; ok {
+				continue
+			}
+			seen[resourceKey] = struct{}{}
+
+			if resourceName, ok := e.names.lookup(typeResource, resourceKey); ok {
+				dependsOn = append(dependsOn, hclwrite.TokensForTraversal(hcl.Traversal{
+					hcl.TraverseRoot{Name: typeResource},
+					hcl.TraverseAttr{Name: resourceName},
+				}))
+			}
+		}
+		if len(dependsOn) > 0 {
+			sort.Slice(dependsOn, func(i, j int) bool { return string(dependsOn[i].Bytes()) < string(dependsOn[j].Bytes()) })
+			body.SetAttributeRaw("depends_on", hclwrite.TokensForTuple(dependsOn))
+		}
+	}
+
+	for _, resource := range e.resources {
+		for _, role := range resource.Roles {
+			id := resource.Key + ":" + role.Key
+			name := e.names.assign(typeRole, id, resource.Key, role.Key)
+			body := e.addResource(typeRole, name, id)
+
+			body.SetAttributeValue("key", cty.StringVal(role.Key))
+			body.SetAttributeValue("name", cty.StringVal(role.Name))
+			setOptionalString(body, "description", role.Description)
+			e.setReference(body, "resource", typeResource, resource.Key, "key", resource.Key)
+			body.SetAttributeValue("permissions", stringList(role.Permissions))
+			body.SetAttributeValue("extends", stringList(role.Extends))
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportRelations(_ context.Context) error {
+	for _, resource := range e.resources {
+		for _, relation := range resource.Relations {
+			id := resource.Key + ":" + relation.Key
+			name := e.names.assign(typeRelation, id, resource.Key, relation.Key)
+			body := e.addResource(typeRelation, name, id)
+
+			body.SetAttributeValue("key", cty.StringVal(relation.Key))
+			body.SetAttributeValue("name", cty.StringVal(relation.Name))
+			setOptionalString(body, "description", relation.Description)
+			e.setReference(body, "subject_resource", typeResource, relation.SubjectResource, "key", relation.SubjectResource)
+			e.setReference(body, "object_resource", typeResource, resource.Key, "key", resource.Key)
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportRoleDerivations(_ context.Context) error {
+	for _, resource := range e.resources {
+		for _, role := range resource.Roles {
+			if role.GrantedTo == nil {
+				continue
+			}
+
+			rules := append([]models.DerivedRoleRuleRead(nil), role.GrantedTo.UsersWithRole...)
+			sort.Slice(rules, func(i, j int) bool {
+				return derivationId(resource.Key, role.Key, rules[i]) < derivationId(resource.Key, role.Key, rules[j])
+			})
+
+			for _, rule := range rules {
+				id := derivationId(resource.Key, role.Key, rule)
+				name := e.names.assign(typeRoleDerivation, id, resource.Key, role.Key, "from", rule.OnResource, rule.Role)
+				body := e.addResource(typeRoleDerivation, name, id)
+
+				e.setReference(body, "resource", typeResource, resource.Key, "key", resource.Key)
+				e.setReference(body, "to_role", typeRole, resource.Key+":"+role.Key, "key", role.Key)
+				e.setReference(body, "on_resource", typeResource, rule.OnResource, "key", rule.OnResource)
+				e.setReference(body, "role", typeRole, rule.OnResource+":"+rule.Role, "key", rule.Role)
+				e.setReference(body, "linked_by", typeRelation, resource.Key+":"+rule.LinkedByRelation, "key", rule.LinkedByRelation)
+			}
+		}
+	}
+
+	return nil
+}
+
+// derivationId is the import ID of a role derivation:
+// resource:to_role:on_resource:role:linked_by.
+func derivationId(resource string, toRole string, rule models.DerivedRoleRuleRead) string {
+	return strings.Join([]string{resource, toRole, rule.OnResource, rule.Role, rule.LinkedByRelation}, ":")
+}
+
+func (e *Exporter) exportConditionSets(ctx context.Context) error {
+	conditionSets, err := common.ListAllPages(func(page int, perPage int) ([]models.ConditionSetRead, error) {
+		return e.client.Api.ConditionSets.List(ctx, page, perPage)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(conditionSets, func(i, j int) bool { return conditionSets[i].Key < conditionSets[j].Key })
+
+	for _, conditionSet := range conditionSets {
+		if conditionSet.Autogenerated != nil && *conditionSet.Autogenerated {
+			continue
+		}
+
+		resourceType := typeUserSet
+		if conditionSet.Type != nil && *conditionSet.Type == models.RESOURCESET {
+			resourceType = typeResourceSet
+		}
+
+		name := e.names.assign(resourceType, conditionSet.Key, conditionSet.Key)
+		body := e.addResource(resourceType, name, conditionSet.Key)
+
+		conditions, err := json.Marshal(conditionSet.Conditions)
+		if err != nil {
+			return err
+		}
+
+		body.SetAttributeValue("key", cty.StringVal(conditionSet.Key))
+		body.SetAttributeValue("name", cty.StringVal(conditionSet.Name))
+		setOptionalString(body, "description", conditionSet.Description)
+		if resourceType == typeResourceSet && conditionSet.Resource != nil {
+			e.setReference(body, "resource", typeResource, conditionSet.Resource.Key, "key", conditionSet.Resource.Key)
+		}
+		body.SetAttributeValue("conditions", cty.StringVal(string(conditions)))
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportConditionSetRules(ctx context.Context) error {
+	rules, err := e.client.Api.ConditionSets.ListSetPermissions(ctx, "", "", "")
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return conditionSetRuleId(rules[i]) < conditionSetRuleId(rules[j]) })
+
+	for _, rule := range rules {
+		userSetName, userSetOk := e.names.lookup(typeUserSet, rule.UserSet)
+		resourceSetName, resourceSetOk := e.names.lookup(typeResourceSet, rule.ResourceSet)
+
+		// Rules on autogenerated sets belong to the objects that generated them.
+		if !userSetOk || !resourceSetOk {
+			continue
+		}
+
+		id := conditionSetRuleId(rule)
+		name := e.names.assign(typeConditionSetRule, id, userSetName, rule.Permission, resourceSetName)
+		body := e.addResource(typeConditionSetRule, name, id)
+
+		e.setReference(body, "user_set", typeUserSet, rule.UserSet, "key", rule.UserSet)
+		body.SetAttributeValue("permission", cty.StringVal(rule.Permission))
+		e.setReference(body, "resource_set", typeResourceSet, rule.ResourceSet, "key", rule.ResourceSet)
+	}
+
+	return nil
+}
+
+// conditionSetRuleId is the import ID of a condition set rule:
+// user_set,permission,resource_set.
+func conditionSetRuleId(rule models.ConditionSetRuleRead) string {
+	return strings.Join([]string{rule.UserSet, rule.Permission, rule.ResourceSet}, ",")
+}
+
+func (e *Exporter) exportTenants(ctx context.Context) error {
+	tenants, err := common.ListAllPages(func(page int, perPage int) ([]models.TenantRead, error) {
+		return e.client.Api.Tenants.List(ctx, page, perPage)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Key < tenants[j].Key })
+
+	for _, tenant := range tenants {
+		name := e.names.assign(typeTenant, tenant.Key, tenant.Key)
+		body := e.addResource(typeTenant, name, tenant.Key)
+
+		body.SetAttributeValue("key", cty.StringVal(tenant.Key))
+		body.SetAttributeValue("name", cty.StringVal(tenant.Name))
+		setOptionalString(body, "description", tenant.Description)
+
+		if len(tenant.Attributes) > 0 {
+			attributes, err := jsonValue(tenant.Attributes)
+			if err != nil {
+				return err
+			}
+			body.SetAttributeValue("attributes", attributes)
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportProxyConfigs(ctx context.Context) error {
+	proxyConfigs, err := common.ListAllPages(func(page int, perPage int) ([]models.ProxyConfigRead, error) {
+		return e.client.Api.ProxyConfigs.List(ctx, page, perPage)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(proxyConfigs, func(i, j int) bool { return proxyConfigs[i].Key < proxyConfigs[j].Key })
+
+	for _, proxyConfig := range proxyConfigs {
+		name := e.names.assign(typeProxyConfig, proxyConfig.Key, proxyConfig.Key)
+
+		authMechanism := string(models.BEARER)
+		if proxyConfig.AuthMechanism != nil {
+			authMechanism = string(*proxyConfig.AuthMechanism)
+		}
+
+		body := e.addResource(typeProxyConfig, name, proxyConfig.Key)
+
+		body.SetAttributeValue("key", cty.StringVal(proxyConfig.Key))
+		body.SetAttributeValue("name", cty.StringVal(proxyConfig.Name))
+		body.SetAttributeValue("auth_mechanism", cty.StringVal(authMechanism))
+
+		// Secrets are not written to the configuration. A sensitive variable
+		// is declared for each one instead.
+		secretAttribute := "bearer"
+		secretType := hclwrite.TokensForIdentifier("string")
+		switch authMechanism {
+		case string(models.BASIC):
+			secretAttribute = "basic"
+		case string(models.HEADERS):
+			secretAttribute = "headers"
+			secretType = hclwrite.TokensForFunctionCall("map", hclwrite.TokensForIdentifier("string"))
+		}
+
+		variableName := name + "_secret"
+		variable := e.file.Body().AppendNewBlock("variable", []string{variableName}).Body()
+		variable.SetAttributeRaw("type", secretType)
+		variable.SetAttributeValue("sensitive", cty.True)
+		e.file.Body().AppendNewline()
+
+		body.SetAttributeRaw("auth_secret", hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{{
+			Name: hclwrite.TokensForIdentifier(secretAttribute),
+			Value: hclwrite.TokensForTraversal(hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: variableName},
+			}),
+		}}))
+
+		rules := make([]cty.Value, 0, len(proxyConfig.MappingRules))
+		for _, rule := range proxyConfig.MappingRules {
+			values := map[string]cty.Value{
+				"url":         cty.StringVal(rule.Url),
+				"http_method": cty.StringVal(string(rule.HttpMethod)),
+				"resource":    cty.StringVal(rule.Resource),
+			}
+			if rule.Action != nil {
+				values["action"] = cty.StringVal(*rule.Action)
+			}
+			if rule.Priority != nil {
+				values["priority"] = cty.NumberIntVal(int64(*rule.Priority))
+			}
+			if rule.Headers != nil && len(*rule.Headers) > 0 {
+				headers := map[string]cty.Value{}
+				for key, value := range *rule.Headers {
+					headers[key] = cty.StringVal(value)
+				}
+				values["headers"] = cty.MapVal(headers)
+			}
+			rules = append(rules, cty.ObjectVal(values))
+		}
+		body.SetAttributeValue("mapping_rules", tupleOrEmpty(rules))
+	}
+
+	return nil
+}
+
+// addResource appends a resource block followed by an import block for it,
+// and returns the body of the resource block to be filled in.
+func (e *Exporter) addResource(resourceType string, name string, importId string) *hclwrite.Body {
+	root := e.file.Body()
+
+	body := root.AppendNewBlock("resource", []string{resourceType, name}).Body()
+	root.AppendNewline()
+
+	importBody := root.AppendNewBlock("import", nil).Body()
+	importBody.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	})
+	importBody.SetAttributeValue("id", cty.StringVal(importId))
+	root.AppendNewline()
+
+	return body
+}
+
+// setReference sets attribute to a reference to another exported object, or
+// to the literal fallback when that object is not part of the export.
+func (e *Exporter) setReference(body *hclwrite.Body, attribute string, resourceType string, id string, referencedAttribute string, fallback string) {
+	name, ok := e.names.lookup(resourceType, id)
+	if !ok {
+		body.SetAttributeValue(attribute, cty.StringVal(fallback))
+		return
+	}
+
+	body.SetAttributeTraversal(attribute, hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+		hcl.TraverseAttr{Name: referencedAttribute},
+	})
+}
+
+func setOptionalString(body *hclwrite.Body, attribute string, value *string) {
+	if value != nil && *value != "" {
+		body.SetAttributeValue(attribute, cty.StringVal(*value))
+	}
+}
+
+func stringList(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	elements := make([]cty.Value, len(sorted))
+	for i, value := range sorted {
+		elements[i] = cty.StringVal(value)
+	}
+	return cty.ListVal(elements)
+}
+
+// jsonValue converts data decoded from JSON into the value of an equivalent
+// HCL literal, so free-form attributes are written as native objects.
+func jsonValue(value any) (cty.Value, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	valueType, err := ctyjson.ImpliedType(encoded)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(encoded, valueType)
+}
+
+func objectOrEmpty(attributes map[string]cty.Value) cty.Value {
+	if len(attributes) == 0 {
+		return cty.EmptyObjectVal
+	}
+	return cty.ObjectVal(attributes)
+}
+
+func tupleOrEmpty(elements []cty.Value) cty.Value {
+	if len(elements) == 0 {
+		return cty.EmptyTupleVal
+	}
+	return cty.TupleVal(elements)
+}
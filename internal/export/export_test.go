@@ -0,0 +1,87 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestAddResourceWritesImportBlock(t *testing.T) {
+	e := &Exporter{file: hclwrite.NewEmptyFile(), names: newNames()}
+
+	body := e.addResource(typeRole, "document_editor", "document:editor")
+	body.SetAttributeValue("key", cty.StringVal("editor"))
+
+	want := `resource "permitio_role" "document_editor" {
+  key = "editor"
+}
+
+import {
+  to = permitio_role.document_editor
+  id = "document:editor"
+}
+
+`
+	if got := string(e.file.Bytes()); got != want {
+		t.Errorf("addResource() wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSetReference(t *testing.T) {
+	e := &Exporter{file: hclwrite.NewEmptyFile(), names: newNames()}
+	e.names.assign(typeResource, "document", "document")
+
+	body := e.file.Body()
+	e.setReference(body, "resource", typeResource, "document", "key", "document")
+	e.setReference(body, "subject", typeResource, "folder", "key", "folder")
+
+	want := `resource = permitio_resource.document.key
+subject  = "folder"
+`
+	if got := string(e.file.Bytes()); got != want {
+		t.Errorf("setReference() wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDerivationId(t *testing.T) {
+	rule := models.DerivedRoleRuleRead{OnResource: "folder", Role: "editor", LinkedByRelation: "parent"}
+
+	want := "document:editor:folder:editor:parent"
+	if got := derivationId("document", "editor", rule); got != want {
+		t.Errorf("derivationId() = %q, want %q", got, want)
+	}
+}
+
+func TestStringListIsSorted(t *testing.T) {
+	got := stringList([]string{"document:write", "document:read"})
+	want := cty.ListVal([]cty.Value{cty.StringVal("document:read"), cty.StringVal("document:write")})
+
+	if !got.RawEquals(want) {
+		t.Errorf("stringList() = %#v, want %#v", got, want)
+	}
+	if got := stringList(nil); !got.RawEquals(cty.ListValEmpty(cty.String)) {
+		t.Errorf("stringList(nil) = %#v, want an empty list", got)
+	}
+}
+
+func TestJsonValueWritesNativeObject(t *testing.T) {
+	value, err := jsonValue(map[string]any{"plan": "pro", "seats": 10, "regions": []any{"eu", "us"}})
+	if err != nil {
+		t.Fatalf("jsonValue() error = %v", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	f.Body().SetAttributeValue("attributes", value)
+
+	want := `attributes = {
+  plan    = "pro"
+  regions = ["eu", "us"]
+  seats   = 10
+}
+`
+	if got := string(f.Bytes()); got != want {
+		t.Errorf("jsonValue() wrote\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// names hands out Terraform resource names that are unique per resource type
+// and remembers which name was given to which Permit object, so later blocks
+// can reference earlier ones.
+type names struct {
+	byType map[string]map[string]string
+	used   map[string]map[string]struct{}
+}
+
+func newNames() *names {
+	return &names{
+		byType: map[string]map[string]string{},
+		used:   map[string]map[string]struct{}{},
+	}
+}
+
+// assign returns a new name for the object identified by id, derived from the
+// given parts.
+func (n *names) assign(resourceType string, id string, parts ...string) string {
+	if n.byType[resourceType] == nil {
+		n.byType[resourceType] = map[string]string{}
+		n.used[resourceType] = map[string]struct{}{}
+	}
+
+	base := identifier(strings.Join(parts, "_"))
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := n.used[resourceType][name]; !taken {
+			break
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+
+	n.used[resourceType][name] = struct{}{}
+	n.byType[resourceType][id] = name
+	return name
+}
+
+// lookup returns the name previously assigned to the object identified by id.
+func (n *names) lookup(resourceType string, id string) (string, bool) {
+	name, ok := n.byType[resourceType][id]
+	return name, ok
+}
+
+// identifier turns a Permit key into a valid Terraform identifier. Characters
+// that are not allowed are replaced by underscores, and a leading underscore
+// is added when the key does not start with a letter.
+func identifier(key string) string {
+	var b strings.Builder
+
+	for _, r := range key {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+
+	result := b.String()
+	if result == "" || !unicode.IsLetter(rune(result[0])) && result[0] != '_' {
+		result = "_" + result
+	}
+
+	return result
+}
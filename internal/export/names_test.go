@@ -0,0 +1,48 @@
+package export
+
+import "testing"
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"document":      "document",
+		"acme-corp":     "acme-corp",
+		"document:read": "document_read",
+		"123":           "_123",
+		"-tenant":       "_-tenant",
+		"_private":      "_private",
+		"café":          "caf_",
+		"":              "_",
+	}
+
+	for key, want := range tests {
+		t.Run(key, func(t *testing.T) {
+			if got := identifier(key); got != want {
+				t.Errorf("identifier(%q) = %q, want %q", key, got, want)
+			}
+		})
+	}
+}
+
+func TestNamesAssign(t *testing.T) {
+	n := newNames()
+
+	if got := n.assign(typeRole, "editor", "editor"); got != "editor" {
+		t.Errorf("assign(editor) = %q, want %q", got, "editor")
+	}
+	if got := n.assign(typeRole, "document:editor", "document", "editor"); got != "document_editor" {
+		t.Errorf("assign(document:editor) = %q, want %q", got, "document_editor")
+	}
+	if got := n.assign(typeRole, "document_editor", "document_editor"); got != "document_editor_2" {
+		t.Errorf("assign(document_editor) = %q, want %q", got, "document_editor_2")
+	}
+	if got := n.assign(typeResource, "editor", "editor"); got != "editor" {
+		t.Errorf("assign(editor) for another type = %q, want %q", got, "editor")
+	}
+
+	if got, ok := n.lookup(typeRole, "document:editor"); !ok || got != "document_editor" {
+		t.Errorf("lookup(document:editor) = %q, %v, want %q, true", got, ok, "document_editor")
+	}
+	if _, ok := n.lookup(typeRole, "viewer"); ok {
+		t.Errorf("lookup(viewer) found a name for an unassigned object")
+	}
+}
@@ -0,0 +1,191 @@
+package api_keys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &APIKeyEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &APIKeyEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &APIKeyEphemeralResource{}
+)
+
+// mintedKeyPrivateKey is the private data key holding the ID of a key minted
+// by Open, which Close deletes.
+const mintedKeyPrivateKey = "minted_api_key_id"
+
+func NewAPIKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &APIKeyEphemeralResource{}
+}
+
+type APIKeyEphemeralResource struct {
+	client apiKeysClient
+}
+
+type apiKeyModel struct {
+	Id            types.String `tfsdk:"id"`
+	ProjectId     types.String `tfsdk:"project_id"`
+	EnvironmentId types.String `tfsdk:"environment_id"`
+	AccessLevel   types.String `tfsdk:"access_level"`
+	ApiKey        types.String `tfsdk:"api_key"`
+}
+
+func (r *APIKeyEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_key"
+}
+
+func (r *APIKeyEphemeralResource) Configure(_ context.Context, request ephemeral.ConfigureRequest, response *ephemeral.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if _, ok := request.ProviderData.(*permit.Client); !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *permit.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = newAPIKeysClient()
+}
+
+func (r *APIKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the API key of an environment during a run, without storing it in the Terraform state. " +
+			"When `access_level` is set, a new environment API key is minted instead and deleted again once Terraform no longer needs it. " +
+			"Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the API key",
+			},
+			"project_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The ID of the project. Defaults to the project of the provider API key.",
+			},
+			"environment_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The ID of the environment. Defaults to the environment of the provider API key.",
+			},
+			"access_level": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "When set, mints a short-lived environment API key with this access level, one of `read`, `write` or `admin`, that is deleted at the end of the run.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(models.READ), string(models.WRITE), string(models.ADMIN)),
+				},
+			},
+			"api_key": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret of the API key",
+			},
+		},
+	}
+}
+
+func (r *APIKeyEphemeralResource) Open(ctx context.Context, request ephemeral.OpenRequest, response *ephemeral.OpenResponse) {
+	var model apiKeyModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &model)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	scope, err := r.client.resolveScope(ctx, model.ProjectId.ValueString(), model.EnvironmentId.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Unable to determine the API key environment", err.Error())
+		return
+	}
+
+	var key models.APIKeyRead
+	if model.AccessLevel.IsNull() {
+		key, err = r.client.environmentKey(ctx, *scope.ProjectId, *scope.EnvironmentId)
+	} else {
+		key, err = r.client.create(ctx, models.APIKeyCreate{
+			OrganizationId: scope.OrganizationId,
+			ProjectId:      scope.ProjectId,
+			EnvironmentId:  scope.EnvironmentId,
+			ObjectType:     models.ENV.Ptr(),
+			AccessLevel:    models.MemberAccessLevel(model.AccessLevel.ValueString()).Ptr(),
+		})
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to open API key",
+			fmt.Sprintf("Unable to get an API key of environment %s: %s", *scope.EnvironmentId, err),
+		)
+		return
+	}
+
+	if !model.AccessLevel.IsNull() {
+		id, err := json.Marshal(key.Id)
+		if err != nil {
+			response.Diagnostics.AddError("Unable to store the minted API key ID", err.Error())
+			return
+		}
+		response.Diagnostics.Append(response.Private.SetKey(ctx, mintedKeyPrivateKey, id)...)
+	}
+
+	if key.Secret == nil {
+		response.Diagnostics.AddError(
+			"API key has no secret",
+			fmt.Sprintf("Permit.io returned API key %s without its secret.", key.Id),
+		)
+
+		// Close is not called when Open fails, so the minted key would be left behind.
+		if !model.AccessLevel.IsNull() {
+			if err := r.client.delete(ctx, key.Id); err != nil {
+				response.Diagnostics.AddError(
+					"Unable to delete minted API key",
+					fmt.Sprintf("Unable to delete API key %s: %s", key.Id, err),
+				)
+			}
+		}
+		return
+	}
+
+	model.Id = types.StringValue(key.Id)
+	model.ProjectId = types.StringValue(*scope.ProjectId)
+	model.EnvironmentId = types.StringValue(*scope.EnvironmentId)
+	model.ApiKey = types.StringValue(*key.Secret)
+
+	response.Diagnostics.Append(response.Result.Set(ctx, &model)...)
+}
+
+// Close deletes the API key minted by Open, if any.
+func (r *APIKeyEphemeralResource) Close(ctx context.Context, request ephemeral.CloseRequest, response *ephemeral.CloseResponse) {
+	data, diags := request.Private.GetKey(ctx, mintedKeyPrivateKey)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() || data == nil {
+		return
+	}
+
+	var id string
+	if err := json.Unmarshal(data, &id); err != nil {
+		response.Diagnostics.AddError("Unable to read the minted API key ID", err.Error())
+		return
+	}
+
+	if err := r.client.delete(ctx, id); err != nil {
+		response.Diagnostics.AddError(
+			"Unable to delete minted API key",
+			fmt.Sprintf("Unable to delete API key %s: %s", id, err),
+		)
+	}
+}
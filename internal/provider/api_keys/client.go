@@ -0,0 +1,141 @@
+package api_keys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+// apiKeysClient calls the API key and PDP config endpoints directly, as the
+// Permit.io SDK does not expose them.
+type apiKeysClient struct {
+	apiUrl     string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func newAPIKeysClient() apiKeysClient {
+	apiUrl := config.GetGlobalApiUrl()
+	if apiUrl == "" {
+		apiUrl = config.DefaultApiUrl
+	}
+
+	return apiKeysClient{
+		apiUrl:     strings.TrimSuffix(apiUrl, "/"),
+		apiKey:     config.GetGlobalApiKey(),
+		httpClient: config.GetHTTPClient(),
+	}
+}
+
+// scope returns the organization, project and environment of the provider API
+// key. The project and environment are only set for keys scoped to them.
+func (c *apiKeysClient) scope(ctx context.Context) (models.APIKeyScopeRead, error) {
+	var scope models.APIKeyScopeRead
+	err := c.do(ctx, http.MethodGet, "/v2/api-key/scope", nil, &scope)
+	return scope, err
+}
+
+// environmentKey returns the environment-level API key of an environment,
+// including its secret.
+func (c *apiKeysClient) environmentKey(ctx context.Context, projectId, environmentId string) (models.APIKeyRead, error) {
+	var key models.APIKeyRead
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v2/api-key/%s/%s", projectId, environmentId), nil, &key)
+	return key, err
+}
+
+func (c *apiKeysClient) create(ctx context.Context, create models.APIKeyCreate) (models.APIKeyRead, error) {
+	var key models.APIKeyRead
+	err := c.do(ctx, http.MethodPost, "/v2/api-key", create, &key)
+	return key, err
+}
+
+func (c *apiKeysClient) delete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v2/api-key/"+id, nil, nil)
+}
+
+// pdpConfigs returns the PDP configs of an environment, whose client secrets
+// are the API keys PDPs authenticate with.
+func (c *apiKeysClient) pdpConfigs(ctx context.Context, projectId, environmentId string) ([]models.PDPConfigRead, error) {
+	var configs []models.PDPConfigRead
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v2/pdps/%s/%s/configs", projectId, environmentId), nil, &configs)
+	return configs, err
+}
+
+// do sends a request with body encoded as JSON, when set, and decodes the
+// response into result, when set. Responses carry secrets, so they are never
+// served from or stored in the read cache.
+func (c *apiKeysClient) do(ctx context.Context, method, path string, body any, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewBuffer(bodyJSON)
+	}
+
+	req, err := http.NewRequestWithContext(common.FreshRead(ctx), method, c.apiUrl+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// resolveScope fills in the project and environment of an ephemeral resource
+// from the provider API key when they are not configured.
+func (c *apiKeysClient) resolveScope(ctx context.Context, projectId, environmentId string) (models.APIKeyScopeRead, error) {
+	scope, err := c.scope(ctx)
+	if err != nil {
+		return scope, err
+	}
+
+	if projectId != "" {
+		scope.ProjectId = &projectId
+	}
+	if environmentId != "" {
+		scope.EnvironmentId = &environmentId
+	}
+
+	if scope.ProjectId == nil || scope.EnvironmentId == nil {
+		return scope, fmt.Errorf("the provider API key is not scoped to an environment, set project_id and environment_id")
+	}
+
+	return scope, nil
+}
@@ -0,0 +1,123 @@
+package api_keys
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *apiKeysClient {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &apiKeysClient{apiUrl: server.URL, apiKey: "permit_key_test", httpClient: server.Client()}
+}
+
+func TestResolveScope(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/api-key/scope" || r.Header.Get("Authorization") != "Bearer permit_key_test" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"organization_id": "org", "project_id": "proj", "environment_id": "env"}`))
+	})
+
+	scope, err := client.resolveScope(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("resolveScope() error = %v", err)
+	}
+	if *scope.ProjectId != "proj" || *scope.EnvironmentId != "env" {
+		t.Errorf("resolveScope() = %s/%s, want proj/env", *scope.ProjectId, *scope.EnvironmentId)
+	}
+
+	scope, err = client.resolveScope(context.Background(), "other", "staging")
+	if err != nil || *scope.ProjectId != "other" || *scope.EnvironmentId != "staging" {
+		t.Errorf("resolveScope(other, staging) = %v, %v, want other/staging", scope, err)
+	}
+}
+
+func TestResolveScopeOrganizationKey(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"organization_id": "org"}`))
+	})
+
+	if _, err := client.resolveScope(context.Background(), "", ""); err == nil {
+		t.Error("resolveScope() of an organization key without project_id and environment_id should fail")
+	}
+}
+
+func TestCreateAndDelete(t *testing.T) {
+	var created models.APIKeyCreate
+	deleted := ""
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/api-key":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "key-1", "organization_id": "org", "owner_type": "member", "secret": "permit_key_minted", "created_at": "2026-01-01T00:00:00Z", "created_by_member": {}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/v2/api-key/key-1":
+			deleted = "key-1"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	})
+
+	key, err := client.create(context.Background(), models.APIKeyCreate{
+		OrganizationId: "org",
+		AccessLevel:    models.READ.Ptr(),
+	})
+	if err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+	if key.Secret == nil || *key.Secret != "permit_key_minted" {
+		t.Errorf("create() secret = %v, want permit_key_minted", key.Secret)
+	}
+	if created.AccessLevel == nil || *created.AccessLevel != models.READ {
+		t.Errorf("create() sent access level %v, want read", created.AccessLevel)
+	}
+
+	if err := client.delete(context.Background(), key.Id); err != nil || deleted != "key-1" {
+		t.Errorf("delete() = %v, deleted %q, want key-1", err, deleted)
+	}
+}
+
+func TestRequestError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+
+	if _, err := client.pdpConfigs(context.Background(), "proj", "env"); err == nil {
+		t.Error("pdpConfigs() of a forbidden request should fail")
+	}
+}
+
+func TestSecretsAreNotCached(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"id": "key-1", "organization_id": "org", "owner_type": "member", "secret": "permit_key_env", "created_at": "2026-01-01T00:00:00Z"}`))
+	})
+	client.httpClient = &http.Client{Transport: common.NewCachingTransport(nil, time.Minute)}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.environmentKey(context.Background(), "proj", "env"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
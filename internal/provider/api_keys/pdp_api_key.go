@@ -0,0 +1,131 @@
+package api_keys
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &PDPAPIKeyEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &PDPAPIKeyEphemeralResource{}
+)
+
+func NewPDPAPIKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &PDPAPIKeyEphemeralResource{}
+}
+
+type PDPAPIKeyEphemeralResource struct {
+	client apiKeysClient
+}
+
+type pdpAPIKeyModel struct {
+	ProjectId     types.String `tfsdk:"project_id"`
+	EnvironmentId types.String `tfsdk:"environment_id"`
+	PDPConfigId   types.String `tfsdk:"pdp_config_id"`
+	Name          types.String `tfsdk:"name"`
+	ApiKey        types.String `tfsdk:"api_key"`
+}
+
+func (r *PDPAPIKeyEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pdp_api_key"
+}
+
+func (r *PDPAPIKeyEphemeralResource) Configure(_ context.Context, request ephemeral.ConfigureRequest, response *ephemeral.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if _, ok := request.ProviderData.(*permit.Client); !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *permit.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = newAPIKeysClient()
+}
+
+func (r *PDPAPIKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the API key a PDP authenticates with during a run, without storing it in the Terraform state, " +
+			"for example to pass it to a Kubernetes secret through a write-only attribute. Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The ID of the project of the PDP. Defaults to the project of the provider API key.",
+			},
+			"environment_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The ID of the environment of the PDP. Defaults to the environment of the provider API key.",
+			},
+			"pdp_config_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The ID of the PDP config to fetch the key of. Defaults to the first PDP config of the environment.",
+			},
+			"name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The name of the PDP config",
+			},
+			"api_key": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The API key of the PDP",
+			},
+		},
+	}
+}
+
+func (r *PDPAPIKeyEphemeralResource) Open(ctx context.Context, request ephemeral.OpenRequest, response *ephemeral.OpenResponse) {
+	var model pdpAPIKeyModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &model)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	scope, err := r.client.resolveScope(ctx, model.ProjectId.ValueString(), model.EnvironmentId.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Unable to determine the PDP environment", err.Error())
+		return
+	}
+
+	configs, err := r.client.pdpConfigs(ctx, *scope.ProjectId, *scope.EnvironmentId)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to read PDP configs",
+			fmt.Sprintf("Unable to read the PDP configs of environment %s: %s", *scope.EnvironmentId, err),
+		)
+		return
+	}
+
+	for _, pdpConfig := range configs {
+		if !model.PDPConfigId.IsNull() && pdpConfig.Id != model.PDPConfigId.ValueString() {
+			continue
+		}
+
+		model.ProjectId = types.StringValue(pdpConfig.ProjectId)
+		model.EnvironmentId = types.StringValue(pdpConfig.EnvironmentId)
+		model.PDPConfigId = types.StringValue(pdpConfig.Id)
+		model.Name = types.StringPointerValue(pdpConfig.Name)
+		model.ApiKey = types.StringValue(pdpConfig.ClientSecret)
+
+		response.Diagnostics.Append(response.Result.Set(ctx, &model)...)
+		return
+	}
+
+	detail := fmt.Sprintf("Environment %s has no PDP config.", *scope.EnvironmentId)
+	if !model.PDPConfigId.IsNull() {
+		detail = fmt.Sprintf("Environment %s has no PDP config with ID %s.", *scope.EnvironmentId, model.PDPConfigId.ValueString())
+	}
+	response.Diagnostics.AddError("PDP config not found", detail)
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+// ShouldAdoptExisting reports whether a Create that hit a conflict should take
+// over the existing object. A per-resource adopt_existing value wins over the
+// provider-level setting when it is set.
+func ShouldAdoptExisting(override types.Bool) bool {
+	if !override.IsNull() && !override.IsUnknown() {
+		return override.ValueBool()
+	}
+	return config.GetAdoptExisting()
+}
+
+// PlanValueMatches reports whether the value of an existing object already
+// satisfies the planned value. Unknown planned values, i.e. optional computed
+// attributes left unset in the configuration, match anything.
+func PlanValueMatches(planned, existing attr.Value) bool {
+	return planned.IsUnknown() || planned.Equal(existing)
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+func TestShouldAdoptExisting(t *testing.T) {
+	tests := []struct {
+		name     string
+		global   bool
+		override types.Bool
+		want     bool
+	}{
+		{"global off, unset", false, types.BoolNull(), false},
+		{"global on, unset", true, types.BoolNull(), true},
+		{"global on, unknown", true, types.BoolUnknown(), true},
+		{"global off, override on", false, types.BoolValue(true), true},
+		{"global on, override off", true, types.BoolValue(false), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetAdoptExisting(tt.global)
+			t.Cleanup(func() { config.SetAdoptExisting(false) })
+
+			if got := ShouldAdoptExisting(tt.override); got != tt.want {
+				t.Errorf("ShouldAdoptExisting(%v) = %v, want %v", tt.override, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanValueMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		planned  types.String
+		existing types.String
+		want     bool
+	}{
+		{"unknown", types.StringUnknown(), types.StringValue("anything"), true},
+		{"equal", types.StringValue("a"), types.StringValue("a"), true},
+		{"different", types.StringValue("a"), types.StringValue("b"), false},
+		{"null vs value", types.StringNull(), types.StringValue("a"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlanValueMatches(tt.planned, tt.existing); got != tt.want {
+				t.Errorf("PlanValueMatches(%v, %v) = %v, want %v", tt.planned, tt.existing, got, tt.want)
+			}
+		})
+	}
+}
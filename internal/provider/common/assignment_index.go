@@ -0,0 +1,162 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+// RoleAssignmentKey identifies a role assignment. ResourceInstance is
+// "resource:instance" for resource instance assignments and empty for
+// tenant-wide ones.
+type RoleAssignmentKey struct {
+	User             string
+	Role             string
+	Tenant           string
+	ResourceInstance string
+}
+
+// AssignmentScope is the set of role assignments an index loads at once: those
+// of one user in one tenant.
+type AssignmentScope struct {
+	Tenant string
+	User   string
+}
+
+// assignmentIndexTTL is how long the loaded assignments of a user are used
+// before they are listed again.
+const assignmentIndexTTL = 30 * time.Second
+
+// AssignmentIndex loads every role assignment of a user in a tenant with a
+// single paginated listing the first time one of them is looked up, and
+// answers further lookups locally for a short time. It lets a refresh of a
+// user's many assignment resources cost one listing instead of one per
+// resource.
+type AssignmentIndex struct {
+	load func(ctx context.Context, scope AssignmentScope) ([]models.RoleAssignmentRead, error)
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu     sync.Mutex
+	scopes map[AssignmentScope]*scopedAssignments
+}
+
+type scopedAssignments struct {
+	ready       chan struct{}
+	expires     time.Time
+	assignments map[RoleAssignmentKey]models.RoleAssignmentRead
+	err         error
+}
+
+var assignmentIndexes sync.Map
+
+// AssignmentIndexFor returns the index shared by every resource using client.
+func AssignmentIndexFor(client *permit.Client) *AssignmentIndex {
+	index, _ := assignmentIndexes.LoadOrStore(client, NewAssignmentIndex(func(ctx context.Context, scope AssignmentScope) ([]models.RoleAssignmentRead, error) {
+		return ListAllRoleAssignments(ctx, client, scope.User, "", scope.Tenant)
+	}, assignmentIndexTTL))
+	return index.(*AssignmentIndex)
+}
+
+// NewAssignmentIndex returns an index that lists the assignments of a user in
+// a tenant with load, and keeps them for ttl.
+func NewAssignmentIndex(load func(ctx context.Context, scope AssignmentScope) ([]models.RoleAssignmentRead, error), ttl time.Duration) *AssignmentIndex {
+	return &AssignmentIndex{load: load, ttl: ttl, now: time.Now, scopes: map[AssignmentScope]*scopedAssignments{}}
+}
+
+// Lookup returns the role assignment with the given key, loading the
+// assignments of its user and tenant if needed. Fresh reads, made while
+// polling for a write, always load them again.
+func (i *AssignmentIndex) Lookup(ctx context.Context, key RoleAssignmentKey) (models.RoleAssignmentRead, bool, error) {
+	scope := AssignmentScope{Tenant: key.Tenant, User: key.User}
+
+	if isFreshRead(ctx) {
+		i.Invalidate(scope.Tenant, scope.User)
+	}
+
+	i.mu.Lock()
+	entry, loaded := i.scopes[scope]
+	if loaded && !entry.expires.IsZero() && !i.now().Before(entry.expires) {
+		loaded = false
+	}
+	if !loaded {
+		entry = &scopedAssignments{ready: make(chan struct{})}
+		i.scopes[scope] = entry
+	}
+	i.mu.Unlock()
+
+	if !loaded {
+		i.fill(ctx, scope, entry)
+	}
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return models.RoleAssignmentRead{}, false, ctx.Err()
+	}
+
+	if entry.err != nil {
+		return models.RoleAssignmentRead{}, false, entry.err
+	}
+
+	assignment, ok := entry.assignments[key]
+	return assignment, ok, nil
+}
+
+func (i *AssignmentIndex) fill(ctx context.Context, scope AssignmentScope, entry *scopedAssignments) {
+	defer close(entry.ready)
+
+	tflog.Debug(ctx, "Loading role assignments of user", map[string]any{"tenant": scope.Tenant, "user": scope.User})
+	assignments, err := i.load(ctx, scope)
+
+	if err != nil {
+		entry.err = err
+		// Let the next lookup try again.
+		i.mu.Lock()
+		if i.scopes[scope] == entry {
+			delete(i.scopes, scope)
+		}
+		i.mu.Unlock()
+		return
+	}
+
+	entry.assignments = make(map[RoleAssignmentKey]models.RoleAssignmentRead, len(assignments))
+	for _, assignment := range assignments {
+		key := RoleAssignmentKey{User: assignment.User, Role: assignment.Role, Tenant: assignment.Tenant}
+		if assignment.ResourceInstance != nil {
+			key.ResourceInstance = *assignment.ResourceInstance
+		}
+		entry.assignments[key] = assignment
+	}
+
+	i.mu.Lock()
+	entry.expires = i.now().Add(i.ttl)
+	i.mu.Unlock()
+}
+
+// Invalidate drops the loaded assignments of a user in a tenant, after one of
+// them was created or deleted.
+func (i *AssignmentIndex) Invalidate(tenant string, user string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.scopes, AssignmentScope{Tenant: tenant, User: user})
+}
+
+// InvalidateTenant drops the loaded assignments of every user in a tenant,
+// after a write that may change the roles of several users at once, such as a
+// role assigned to a group.
+func (i *AssignmentIndex) InvalidateTenant(tenant string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for scope := range i.scopes {
+		if scope.Tenant == tenant {
+			delete(i.scopes, scope)
+		}
+	}
+}
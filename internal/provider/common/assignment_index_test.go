@@ -0,0 +1,100 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+func TestAssignmentIndex(t *testing.T) {
+	ctx := context.Background()
+	document := "document:readme"
+	calls := map[AssignmentScope]int{}
+	var mu sync.Mutex
+	fail := false
+
+	index := NewAssignmentIndex(func(_ context.Context, scope AssignmentScope) ([]models.RoleAssignmentRead, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[scope]++
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return []models.RoleAssignmentRead{
+			{User: scope.User, Role: "admin", Tenant: scope.Tenant},
+			{User: scope.User, Role: "editor", Tenant: scope.Tenant, ResourceInstance: &document},
+		}, nil
+	}, time.Minute)
+	now := time.Now()
+	index.now = func() time.Time { return now }
+
+	alice := AssignmentScope{Tenant: "default", User: "alice"}
+
+	tests := []struct {
+		key  RoleAssignmentKey
+		want bool
+	}{
+		{RoleAssignmentKey{User: "alice", Role: "admin", Tenant: "default"}, true},
+		{RoleAssignmentKey{User: "alice", Role: "editor", Tenant: "default", ResourceInstance: document}, true},
+		{RoleAssignmentKey{User: "alice", Role: "editor", Tenant: "default"}, false},
+		{RoleAssignmentKey{User: "alice", Role: "viewer", Tenant: "default"}, false},
+		{RoleAssignmentKey{User: "bob", Role: "admin", Tenant: "default"}, true},
+		{RoleAssignmentKey{User: "alice", Role: "admin", Tenant: "other"}, true},
+	}
+
+	for _, tt := range tests {
+		_, found, err := index.Lookup(ctx, tt.key)
+		if err != nil {
+			t.Fatalf("Lookup(%+v) error = %v", tt.key, err)
+		}
+		if found != tt.want {
+			t.Errorf("Lookup(%+v) found = %v, want %v", tt.key, found, tt.want)
+		}
+	}
+
+	if len(calls) != 3 || calls[alice] != 1 {
+		t.Errorf("listings = %v, want one per user and tenant", calls)
+	}
+
+	index.Invalidate("default", "alice")
+	fail = true
+
+	if _, _, err := index.Lookup(ctx, tests[0].key); err == nil {
+		t.Error("Lookup() error = nil, want the listing error")
+	}
+
+	fail = false
+
+	if _, found, err := index.Lookup(ctx, tests[0].key); err != nil || !found {
+		t.Errorf("Lookup() after a failed listing = %v, %v, want it to list again", found, err)
+	}
+
+	if calls[alice] != 3 {
+		t.Errorf("listings of alice = %d, want 3", calls[alice])
+	}
+
+	if _, found, err := index.Lookup(FreshRead(ctx), tests[0].key); err != nil || !found || calls[alice] != 4 {
+		t.Errorf("fresh Lookup() = %v, %v after %d listings, want it to list again", found, err, calls[alice])
+	}
+
+	index.InvalidateTenant("default")
+	index.Lookup(ctx, tests[0].key)
+	index.Lookup(ctx, tests[4].key)
+	index.Lookup(ctx, tests[5].key)
+
+	bob := AssignmentScope{Tenant: "default", User: "bob"}
+	other := AssignmentScope{Tenant: "other", User: "alice"}
+	if calls[alice] != 5 || calls[bob] != 2 || calls[other] != 1 {
+		t.Errorf("listings after invalidating the tenant = %v, want default listed again", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, _, err := index.Lookup(ctx, tests[0].key); err != nil || calls[alice] != 6 {
+		t.Errorf("Lookup() after expiry = %v after %d listings, want it to list again", err, calls[alice])
+	}
+}
@@ -0,0 +1,185 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+// TenantResourceKey is the built-in resource that holds the attribute schema
+// of tenants.
+const TenantResourceKey = "__tenant"
+
+// ListAttributeSchema returns the attribute types declared on a resource, keyed
+// by attribute key.
+func ListAttributeSchema(ctx context.Context, client *permit.Client, resourceKey string) (map[string]models.AttributeType, error) {
+	attributes, err := ListAllPages(func(page int, perPage int) ([]models.ResourceAttributeRead, error) {
+		return client.Api.ResourceAttributes.List(ctx, resourceKey, page, perPage)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]models.AttributeType, len(attributes))
+	for _, attribute := range attributes {
+		declared[attribute.Key] = attribute.Type
+	}
+
+	return declared, nil
+}
+
+// ValidateAttributesSchema checks configured attributes against the attribute
+// schema declared on resourceKey, adding a warning for every key that is not
+// declared and every value whose type does not match. They are not errors, as
+// the schema may be changed by a permitio_resource in the same apply, which is
+// not visible from this plan. Nothing is checked while the attributes are not
+// known yet, or when the resource does not exist yet because it is created in
+// the same apply.
+func ValidateAttributesSchema(ctx context.Context, client *permit.Client, resourceKey string, attributes types.Dynamic, diags *diag.Diagnostics) {
+	if client == nil || resourceKey == "" || attributes.IsNull() || attributes.IsUnknown() || attributes.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	if tfValue, err := attributes.UnderlyingValue().ToTerraformValue(ctx); err != nil || !tfValue.IsFullyKnown() {
+		return
+	}
+
+	values, err := AttributesFromDynamic(ctx, attributes)
+	if err != nil || len(values) == 0 {
+		// Malformed attributes are reported by the attribute validator.
+		return
+	}
+
+	declared, err := ListAttributeSchema(ctx, client, resourceKey)
+
+	if IsNotFoundErr(err) {
+		return
+	}
+
+	if err != nil {
+		diags.AddAttributeWarning(
+			path.Root("attributes"),
+			"Unable to validate attributes",
+			fmt.Sprintf("Could not read the attributes declared on %q, so the configured attributes were not checked: %s", resourceKey, err),
+		)
+		return
+	}
+
+	for _, problem := range AttributeSchemaProblems(values, declared) {
+		diags.AddAttributeWarning(
+			path.Root("attributes"),
+			"Attributes do not match the declared schema",
+			fmt.Sprintf("%s on %q. Applying will fail unless the attribute schema is changed in the same apply.", problem, resourceKey),
+		)
+	}
+}
+
+// AttributeSchemaProblems describes every attribute in values that is not
+// declared, or whose value does not match the declared type, sorted by key.
+func AttributeSchemaProblems(values map[string]any, declared map[string]models.AttributeType) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+
+	for _, key := range keys {
+		attributeType, ok := declared[key]
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("Attribute %q is not declared; declared attributes are [%s]", key, strings.Join(declaredKeys(declared), ", ")))
+			continue
+		}
+
+		if !AttributeValueMatches(attributeType, values[key]) {
+			problems = append(problems, fmt.Sprintf("Attribute %q must be of type %s, got %s", key, attributeType, describeValue(values[key])))
+		}
+	}
+
+	return problems
+}
+
+// attributeValueMatchers checks the values of every attribute type of
+// models.AllowedAttributeTypeEnumValues.
+var attributeValueMatchers = map[models.AttributeType]func(value any) bool{
+	models.BOOL: func(value any) bool {
+		_, ok := value.(bool)
+		return ok
+	},
+	models.NUMBER: func(value any) bool {
+		switch value.(type) {
+		case int64, float64:
+			return true
+		}
+		return false
+	},
+	models.STRING: func(value any) bool {
+		_, ok := value.(string)
+		return ok
+	},
+	models.TIME: func(value any) bool {
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	models.ARRAY: func(value any) bool {
+		_, ok := value.([]any)
+		return ok
+	},
+	models.JSON: func(value any) bool {
+		_, ok := value.(map[string]any)
+		return ok
+	},
+}
+
+// AttributeValueMatches reports whether a value decoded from configuration
+// holds data of the given attribute type. Types the SDK does not know about
+// are accepted, so that new types added to Permit are not rejected.
+func AttributeValueMatches(attributeType models.AttributeType, value any) bool {
+	matches, ok := attributeValueMatchers[attributeType]
+	if value == nil || !attributeType.IsValid() || !ok {
+		return true
+	}
+
+	return matches(value)
+}
+
+func describeValue(value any) string {
+	switch value.(type) {
+	case bool:
+		return "bool"
+	case int64, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func declaredKeys(declared map[string]models.AttributeType) []string {
+	keys := make([]string, 0, len(declared))
+	for key := range declared {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+func TestAttributeValueMatches(t *testing.T) {
+	tests := []struct {
+		attributeType models.AttributeType
+		value         any
+		want          bool
+	}{
+		{models.BOOL, true, true},
+		{models.BOOL, "true", false},
+		{models.NUMBER, int64(10), true},
+		{models.NUMBER, 0.5, true},
+		{models.NUMBER, "10", false},
+		{models.STRING, "pro", true},
+		{models.STRING, int64(1), false},
+		{models.TIME, "2024-05-01T12:00:00Z", true},
+		{models.TIME, "2024-05-01T12:00:00.123+02:00", true},
+		{models.TIME, "yesterday", false},
+		{models.ARRAY, []any{"eu", "us"}, true},
+		{models.ARRAY, map[string]any{}, false},
+		{models.JSON, map[string]any{"api": int64(1000)}, true},
+		{models.JSON, "{}", false},
+		{models.STRING, nil, true},
+		{models.AttributeType("geo"), "anything", true},
+	}
+
+	for _, tt := range tests {
+		if got := AttributeValueMatches(tt.attributeType, tt.value); got != tt.want {
+			t.Errorf("AttributeValueMatches(%q, %#v) = %v, want %v", tt.attributeType, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAttributeValueMatchersCoverTypes(t *testing.T) {
+	for _, attributeType := range models.AllowedAttributeTypeEnumValues {
+		if _, ok := attributeValueMatchers[attributeType]; !ok {
+			t.Errorf("attribute type %q has no value matcher", attributeType)
+		}
+	}
+}
+
+func TestAttributeSchemaProblems(t *testing.T) {
+	declared := map[string]models.AttributeType{
+		"plan":  models.STRING,
+		"seats": models.NUMBER,
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   []string
+	}{
+		{"valid", map[string]any{"plan": "pro", "seats": int64(10)}, nil},
+		{"unknown key", map[string]any{"plan": "pro", "region": "eu"}, []string{
+			`Attribute "region" is not declared; declared attributes are [plan, seats]`,
+		}},
+		{"wrong type", map[string]any{"seats": "10", "plan": true}, []string{
+			`Attribute "plan" must be of type string, got bool`,
+			`Attribute "seats" must be of type number, got string`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AttributeSchemaProblems(tt.values, declared); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AttributeSchemaProblems() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
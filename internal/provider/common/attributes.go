@@ -0,0 +1,392 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Tenants, resource instances and users carry free-form attributes. They are
+// exposed as dynamic values so that configuration can use native objects, and
+// other resources can reference individual keys. Earlier versions of the
+// provider took a JSON-encoded string instead, which is still accepted but
+// deprecated.
+
+const (
+	// AttributesModeAuthoritative makes the configured attributes the only
+	// attributes of the object.
+	AttributesModeAuthoritative = "authoritative"
+	// AttributesModeMerge only manages the configured keys and keeps the rest.
+	AttributesModeMerge = "merge"
+)
+
+// AttributesFromDynamic returns the attributes held by a dynamic value. A
+// string is decoded as the legacy JSON form. Null and unknown values give nil.
+func AttributesFromDynamic(ctx context.Context, value types.Dynamic) (map[string]any, error) {
+	if value.IsNull() || value.IsUnknown() || value.IsUnderlyingValueNull() {
+		return nil, nil
+	}
+
+	if value.IsUnderlyingValueUnknown() {
+		return nil, fmt.Errorf("attributes are not known yet")
+	}
+
+	if str, ok := value.UnderlyingValue().(types.String); ok {
+		if str.ValueString() == "" {
+			return nil, nil
+		}
+
+		var attributes map[string]any
+		if err := json.Unmarshal([]byte(str.ValueString()), &attributes); err != nil {
+			return nil, fmt.Errorf("attributes must be a JSON object: %w", err)
+		}
+		return attributes, nil
+	}
+
+	tfValue, err := value.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tfValue.Type().Is(tftypes.Object{}) && !tfValue.Type().Is(tftypes.Map{}) {
+		return nil, fmt.Errorf("attributes must be an object, got %s", tfValue.Type())
+	}
+
+	converted, err := goValue(tfValue)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, _ := converted.(map[string]any)
+	return attributes, nil
+}
+
+// AttributesToDynamic converts attributes read from the API into a dynamic
+// object. Empty attributes are returned as null.
+func AttributesToDynamic(attributes map[string]any) (types.Dynamic, error) {
+	if len(attributes) == 0 {
+		return types.DynamicNull(), nil
+	}
+
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return types.DynamicNull(), fmt.Errorf("unable to encode attributes: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var normalized any
+	if err := decoder.Decode(&normalized); err != nil {
+		return types.DynamicNull(), fmt.Errorf("unable to decode attributes: %w", err)
+	}
+
+	return types.DynamicValue(attrValue(normalized)), nil
+}
+
+// DynamicAttributesEqual reports whether two attribute values hold the same
+// data, regardless of whether they are objects, maps or legacy JSON strings.
+// Null and empty attributes are equal.
+func DynamicAttributesEqual(ctx context.Context, a types.Dynamic, b types.Dynamic) bool {
+	aAttributes, err := AttributesFromDynamic(ctx, a)
+	if err != nil {
+		return false
+	}
+
+	bAttributes, err := AttributesFromDynamic(ctx, b)
+	if err != nil {
+		return false
+	}
+
+	return attributesEqual(aAttributes, bAttributes)
+}
+
+// KeepAttributesForm returns current in the form of prior. When both hold the
+// same data prior is returned unchanged, so the state keeps the exact type
+// written in configuration. When prior uses the legacy string form, current is
+// encoded as a JSON string.
+func KeepAttributesForm(ctx context.Context, prior types.Dynamic, current types.Dynamic) types.Dynamic {
+	if prior.IsUnknown() || prior.IsNull() || prior.IsUnderlyingValueUnknown() {
+		return current
+	}
+
+	if DynamicAttributesEqual(ctx, prior, current) {
+		return prior
+	}
+
+	if _, ok := prior.UnderlyingValue().(types.String); ok {
+		attributes, err := AttributesFromDynamic(ctx, current)
+		if err != nil {
+			return current
+		}
+		if attributes == nil {
+			attributes = map[string]any{}
+		}
+
+		encoded, err := json.Marshal(attributes)
+		if err != nil {
+			return current
+		}
+		return types.DynamicValue(types.StringValue(string(encoded)))
+	}
+
+	return current
+}
+
+// IsMergeMode reports whether attributes_mode is set to merge.
+func IsMergeMode(mode types.String) bool {
+	return mode.ValueString() == AttributesModeMerge
+}
+
+// AttributesModeFromState returns the attributes_mode value to keep in state
+// after a read. Imported objects have no prior value, so they get the schema
+// default instead of showing a diff on the next plan.
+func AttributesModeFromState(mode types.String) types.String {
+	if mode.IsNull() || mode.IsUnknown() {
+		return types.StringValue(AttributesModeAuthoritative)
+	}
+	return mode
+}
+
+// AttributesForState returns the attributes read from Permit as they should be
+// stored in state. In merge mode only the keys held by managed, the planned or
+// prior attributes, are kept, so keys written outside of Terraform never show
+// up as a diff.
+func AttributesForState(ctx context.Context, mode types.String, managed types.Dynamic, current types.Dynamic) types.Dynamic {
+	if IsMergeMode(mode) {
+		current = ManagedAttributes(ctx, managed, current)
+	}
+	return KeepAttributesForm(ctx, managed, current)
+}
+
+// ManagedAttributes returns current restricted to the keys held by managed.
+// Keys in managed that are missing from current are left out, so that they
+// are planned to be written again.
+func ManagedAttributes(ctx context.Context, managed types.Dynamic, current types.Dynamic) types.Dynamic {
+	managedAttributes, err := AttributesFromDynamic(ctx, managed)
+	if err != nil {
+		return current
+	}
+
+	currentAttributes, err := AttributesFromDynamic(ctx, current)
+	if err != nil {
+		return current
+	}
+
+	filtered := map[string]any{}
+	for key := range managedAttributes {
+		if value, ok := currentAttributes[key]; ok {
+			filtered[key] = value
+		}
+	}
+
+	managedCurrent, err := AttributesToDynamic(filtered)
+	if err != nil {
+		return current
+	}
+	return managedCurrent
+}
+
+// MergeAttributes returns the attributes to write in merge mode: the current
+// attributes of the object with the planned keys set on top. Keys held by
+// prior, the attributes Terraform managed before, are dropped unless they are
+// still planned.
+func MergeAttributes(current map[string]any, prior map[string]any, planned map[string]any) map[string]any {
+	merged := make(map[string]any, len(current)+len(planned))
+	for key, value := range current {
+		if _, ok := prior[key]; ok {
+			continue
+		}
+		merged[key] = value
+	}
+
+	for key, value := range planned {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func attributesEqual(a map[string]any, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+
+	// Round-trip both sides through JSON so that numbers compare by value
+	// whatever Go type they were decoded into.
+	aNormalized, aErr := normalizeJSON(a)
+	bNormalized, bErr := normalizeJSON(b)
+	if aErr != nil || bErr != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(aNormalized, bNormalized)
+}
+
+func normalizeJSON(value any) (any, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	err = json.Unmarshal(encoded, &normalized)
+	return normalized, err
+}
+
+// goValue converts a Terraform value into the Go value encoding/json would
+// produce for the same data.
+func goValue(value tftypes.Value) (any, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	if !value.IsKnown() {
+		return nil, fmt.Errorf("attributes are not known yet")
+	}
+
+	switch {
+	case value.Type().Is(tftypes.String):
+		var s string
+		err := value.As(&s)
+		return s, err
+	case value.Type().Is(tftypes.Bool):
+		var b bool
+		err := value.As(&b)
+		return b, err
+	case value.Type().Is(tftypes.Number):
+		var n big.Float
+		if err := value.As(&n); err != nil {
+			return nil, err
+		}
+		if n.IsInt() {
+			if i, accuracy := n.Int64(); accuracy == big.Exact {
+				return i, nil
+			}
+		}
+		f, _ := n.Float64()
+		return f, nil
+	case value.Type().Is(tftypes.List{}), value.Type().Is(tftypes.Set{}), value.Type().Is(tftypes.Tuple{}):
+		var elements []tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+
+		result := make([]any, len(elements))
+		for i, element := range elements {
+			converted, err := goValue(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case value.Type().Is(tftypes.Map{}), value.Type().Is(tftypes.Object{}):
+		var elements map[string]tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+
+		result := make(map[string]any, len(elements))
+		for key, element := range elements {
+			converted, err := goValue(element)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = converted
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unsupported attribute type %s", value.Type())
+}
+
+// attrValue converts a value decoded by encoding/json, with UseNumber, into
+// the attr.Value that an equivalent HCL literal would produce: objects for
+// JSON objects and tuples for JSON arrays.
+func attrValue(value any) attr.Value {
+	switch v := value.(type) {
+	case string:
+		return types.StringValue(v)
+	case bool:
+		return types.BoolValue(v)
+	case json.Number:
+		n, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return types.StringValue(v.String())
+		}
+		return types.NumberValue(n)
+	case []any:
+		elementTypes := make([]attr.Type, len(v))
+		elements := make([]attr.Value, len(v))
+		for i, element := range v {
+			elements[i] = attrValue(element)
+			elementTypes[i] = elements[i].Type(context.Background())
+		}
+		return types.TupleValueMust(elementTypes, elements)
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+		for key, element := range v {
+			attributes[key] = attrValue(element)
+			attributeTypes[key] = attributes[key].Type(context.Background())
+		}
+		return types.ObjectValueMust(attributeTypes, attributes)
+	}
+
+	// JSON null has no type of its own.
+	return types.StringNull()
+}
+
+// AttributesValidator accepts an object or map, or the deprecated JSON string
+// form with a warning.
+func AttributesValidator() validator.Dynamic {
+	return attributesValidator{}
+}
+
+type attributesValidator struct{}
+
+func (v attributesValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v attributesValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be an object of attributes"
+}
+
+func (v attributesValidator) ValidateDynamic(ctx context.Context, request validator.DynamicRequest, response *validator.DynamicResponse) {
+	value := request.ConfigValue
+	if value.IsNull() || value.IsUnknown() || value.IsUnderlyingValueNull() || value.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	// Objects built from other resources may not be known until apply.
+	if tfValue, err := value.UnderlyingValue().ToTerraformValue(ctx); err == nil && !tfValue.IsFullyKnown() {
+		return
+	}
+
+	if _, ok := value.UnderlyingValue().(types.String); ok {
+		response.Diagnostics.AddAttributeWarning(
+			request.Path,
+			"Deprecated attributes format",
+			fmt.Sprintf("%s is set to a JSON-encoded string. Pass an object instead, for example by removing jsonencode(); "+
+				"support for JSON strings will be removed in a future version.", request.Path),
+		)
+	}
+
+	if _, err := AttributesFromDynamic(ctx, value); err != nil {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid attributes",
+			err.Error(),
+		)
+	}
+}
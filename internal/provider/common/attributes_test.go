@@ -0,0 +1,265 @@
+package common
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testAttributesObject() types.Dynamic {
+	return types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{
+			"plan":    types.StringType,
+			"seats":   types.NumberType,
+			"trial":   types.BoolType,
+			"regions": types.TupleType{ElemTypes: []attr.Type{types.StringType, types.StringType}},
+		},
+		map[string]attr.Value{
+			"plan":    types.StringValue("pro"),
+			"seats":   types.NumberValue(big.NewFloat(10)),
+			"trial":   types.BoolValue(false),
+			"regions": types.TupleValueMust([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("eu"), types.StringValue("us")}),
+		},
+	))
+}
+
+func TestAttributesFromDynamic(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		value   types.Dynamic
+		want    string
+		wantErr bool
+	}{
+		{"null", types.DynamicNull(), "", false},
+		{"unknown", types.DynamicUnknown(), "", false},
+		{"object", testAttributesObject(), `{"plan":"pro","regions":["eu","us"],"seats":10,"trial":false}`, false},
+		{"map", types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{"plan": types.StringValue("pro")})), `{"plan":"pro"}`, false},
+		{"legacy string", types.DynamicValue(types.StringValue(`{"seats":10,"plan":"pro"}`)), `{"plan":"pro","seats":10}`, false},
+		{"empty string", types.DynamicValue(types.StringValue("")), "", false},
+		{"invalid string", types.DynamicValue(types.StringValue(`{"plan":`)), "", true},
+		{"number", types.DynamicValue(types.NumberValue(big.NewFloat(1))), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AttributesFromDynamic(ctx, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AttributesFromDynamic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("AttributesFromDynamic() = %v, want nil", got)
+				}
+				return
+			}
+			if want := mustDecode(t, tt.want); !attributesEqual(got, want) {
+				t.Errorf("AttributesFromDynamic() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAttributesToDynamicRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	attributes := mustDecode(t, `{"plan":"pro","seats":10,"ratio":0.5,"trial":false,"regions":["eu","us"],"limits":{"api":1000}}`)
+
+	value, err := AttributesToDynamic(attributes)
+	if err != nil {
+		t.Fatalf("AttributesToDynamic() error = %v", err)
+	}
+	if _, ok := value.UnderlyingValue().(types.Object); !ok {
+		t.Fatalf("AttributesToDynamic() = %T, want an object", value.UnderlyingValue())
+	}
+
+	got, err := AttributesFromDynamic(ctx, value)
+	if err != nil {
+		t.Fatalf("AttributesFromDynamic() error = %v", err)
+	}
+	if !attributesEqual(got, attributes) {
+		t.Errorf("round trip = %v, want %v", got, attributes)
+	}
+
+	if value, err := AttributesToDynamic(nil); err != nil || !value.IsNull() {
+		t.Errorf("AttributesToDynamic(nil) = %v, %v, want null", value, err)
+	}
+
+	if _, err := AttributesToDynamic(map[string]any{"callback": func() {}}); err == nil {
+		t.Errorf("AttributesToDynamic() with a value JSON cannot encode succeeded")
+	}
+}
+
+func TestDynamicAttributesEqual(t *testing.T) {
+	ctx := context.Background()
+	legacy := types.DynamicValue(types.StringValue(`{"trial":false,"seats":10,"regions":["eu","us"],"plan":"pro"}`))
+
+	if !DynamicAttributesEqual(ctx, testAttributesObject(), legacy) {
+		t.Errorf("DynamicAttributesEqual(object, equivalent string) = false, want true")
+	}
+	if !DynamicAttributesEqual(ctx, types.DynamicNull(), types.DynamicValue(types.StringValue("{}"))) {
+		t.Errorf("DynamicAttributesEqual(null, empty object) = false, want true")
+	}
+	if DynamicAttributesEqual(ctx, testAttributesObject(), types.DynamicValue(types.StringValue(`{"plan":"pro"}`))) {
+		t.Errorf("DynamicAttributesEqual(object, different string) = true, want false")
+	}
+}
+
+func TestKeepAttributesForm(t *testing.T) {
+	ctx := context.Background()
+	current := mustDynamic(t, `{"plan":"pro","regions":["eu","us"],"seats":10,"trial":false}`)
+
+	t.Run("equal object keeps prior", func(t *testing.T) {
+		prior := testAttributesObject()
+		if got := KeepAttributesForm(ctx, prior, current); !got.Equal(prior) {
+			t.Errorf("KeepAttributesForm() = %v, want %v", got, prior)
+		}
+	})
+
+	t.Run("equal string keeps prior", func(t *testing.T) {
+		prior := types.DynamicValue(types.StringValue(`{"seats": 10, "plan": "pro", "trial": false, "regions": ["eu", "us"]}`))
+		if got := KeepAttributesForm(ctx, prior, current); !got.Equal(prior) {
+			t.Errorf("KeepAttributesForm() = %v, want %v", got, prior)
+		}
+	})
+
+	t.Run("changed string stays a string", func(t *testing.T) {
+		prior := types.DynamicValue(types.StringValue(`{"plan":"free"}`))
+		got := KeepAttributesForm(ctx, prior, current)
+
+		str, ok := got.UnderlyingValue().(types.String)
+		if !ok {
+			t.Fatalf("KeepAttributesForm() = %T, want a string", got.UnderlyingValue())
+		}
+		if want := `{"plan":"pro","regions":["eu","us"],"seats":10,"trial":false}`; str.ValueString() != want {
+			t.Errorf("KeepAttributesForm() = %s, want %s", str.ValueString(), want)
+		}
+	})
+
+	t.Run("null prior takes current", func(t *testing.T) {
+		if got := KeepAttributesForm(ctx, types.DynamicNull(), current); !got.Equal(current) {
+			t.Errorf("KeepAttributesForm() = %v, want %v", got, current)
+		}
+	})
+}
+
+func TestAttributesForState(t *testing.T) {
+	ctx := context.Background()
+	current := mustDynamic(t, `{"plan":"pro","seat_count":42}`)
+	managed := mustDynamic(t, `{"plan":"free","region":"eu"}`)
+
+	t.Run("authoritative keeps every key", func(t *testing.T) {
+		got := AttributesForState(ctx, types.StringValue(AttributesModeAuthoritative), managed, current)
+		if !DynamicAttributesEqual(ctx, got, current) {
+			t.Errorf("AttributesForState() = %v, want %v", got, current)
+		}
+	})
+
+	t.Run("merge keeps managed keys", func(t *testing.T) {
+		got := AttributesForState(ctx, types.StringValue(AttributesModeMerge), managed, current)
+		if want := mustDynamic(t, `{"plan":"pro"}`); !DynamicAttributesEqual(ctx, got, want) {
+			t.Errorf("AttributesForState() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("merge without managed keys", func(t *testing.T) {
+		got := AttributesForState(ctx, types.StringValue(AttributesModeMerge), types.DynamicUnknown(), current)
+		if !got.IsNull() {
+			t.Errorf("AttributesForState() = %v, want null", got)
+		}
+	})
+
+	t.Run("merge keeps the legacy form", func(t *testing.T) {
+		legacy := types.DynamicValue(types.StringValue(`{"plan":"pro"}`))
+		got := AttributesForState(ctx, types.StringValue(AttributesModeMerge), legacy, current)
+		if !got.Equal(legacy) {
+			t.Errorf("AttributesForState() = %v, want %v", got, legacy)
+		}
+	})
+}
+
+func TestMergeAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		prior   string
+		planned string
+		want    string
+	}{
+		{"keeps unmanaged keys", `{"plan":"free","seat_count":42}`, `{"plan":"free"}`, `{"plan":"pro"}`, `{"plan":"pro","seat_count":42}`},
+		{"removes keys no longer planned", `{"plan":"free","region":"eu","seat_count":42}`, `{"plan":"free","region":"eu"}`, `{"plan":"free"}`, `{"plan":"free","seat_count":42}`},
+		{"adds new keys", `{"seat_count":42}`, `{}`, `{"plan":"pro"}`, `{"plan":"pro","seat_count":42}`},
+		{"nothing planned", `{"plan":"free","seat_count":42}`, `{"plan":"free"}`, `{}`, `{"seat_count":42}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeAttributes(mustDecode(t, tt.current), mustDecode(t, tt.prior), mustDecode(t, tt.planned))
+			if want := mustDecode(t, tt.want); !attributesEqual(got, want) {
+				t.Errorf("MergeAttributes() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAttributesValidator(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		value        types.Dynamic
+		wantWarnings int
+		wantErrors   int
+	}{
+		{"object", testAttributesObject(), 0, 0},
+		{"null", types.DynamicNull(), 0, 0},
+		{"legacy string", types.DynamicValue(types.StringValue(`{"plan":"pro"}`)), 1, 0},
+		{"invalid string", types.DynamicValue(types.StringValue(`[1, 2]`)), 1, 1},
+		{"list", types.DynamicValue(types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a")})), 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := validator.DynamicRequest{Path: path.Root("attributes"), ConfigValue: tt.value}
+			var response validator.DynamicResponse
+
+			AttributesValidator().ValidateDynamic(ctx, request, &response)
+
+			if got := response.Diagnostics.WarningsCount(); got != tt.wantWarnings {
+				t.Errorf("warnings = %d, want %d: %v", got, tt.wantWarnings, response.Diagnostics)
+			}
+			if got := response.Diagnostics.ErrorsCount(); got != tt.wantErrors {
+				t.Errorf("errors = %d, want %d: %v", got, tt.wantErrors, response.Diagnostics)
+			}
+		})
+	}
+}
+
+func mustDecode(t *testing.T, document string) map[string]any {
+	t.Helper()
+
+	value, err := AttributesFromDynamic(context.Background(), types.DynamicValue(types.StringValue(document)))
+	if err != nil {
+		t.Fatalf("decoding %s: %v", document, err)
+	}
+	return value
+}
+
+func mustDynamic(t *testing.T, document string) types.Dynamic {
+	t.Helper()
+
+	value, err := AttributesToDynamic(mustDecode(t, document))
+	if err != nil {
+		t.Fatalf("converting %s: %v", document, err)
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+const (
+	CascadeDelete  = "deleting"
+	CascadeReplace = "replacing"
+)
+
+// PlannedRemoval reports whether the plan removes the object currently in
+// state. It returns CascadeDelete when the object is destroyed, CascadeReplace
+// when one of the replaceOn attributes changes, and an empty string otherwise.
+func PlannedRemoval(ctx context.Context, request resource.ModifyPlanRequest, replaceOn ...path.Path) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if request.State.Raw.IsNull() {
+		return "", diags
+	}
+
+	if request.Plan.Raw.IsNull() {
+		return CascadeDelete, diags
+	}
+
+	for _, attributePath := range replaceOn {
+		var planned, current attr.Value
+
+		diags.Append(request.Plan.GetAttribute(ctx, attributePath, &planned)...)
+		diags.Append(request.State.GetAttribute(ctx, attributePath, &current)...)
+
+		if diags.HasError() {
+			return "", diags
+		}
+
+		if planned.IsUnknown() || !planned.Equal(current) {
+			return CascadeReplace, diags
+		}
+	}
+
+	return "", diags
+}
+
+// Pluralize formats a count followed by the singular or plural form of noun.
+func Pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// DescribeRoleAssignments summarises role assignments as a count and the
+// number of tenants they span, e.g. "412 role assignments across 37 tenants".
+// It returns an empty string when there are none.
+func DescribeRoleAssignments(assignments []models.RoleAssignmentRead) string {
+	if len(assignments) == 0 {
+		return ""
+	}
+
+	tenants := map[string]struct{}{}
+	for _, assignment := range assignments {
+		tenants[assignment.Tenant] = struct{}{}
+	}
+
+	return fmt.Sprintf("%s across %s", Pluralize(len(assignments), "role assignment"), Pluralize(len(tenants), "tenant"))
+}
+
+// DescribeCascade builds the plan warning for an object whose removal drops
+// the given dependents, e.g. "replacing role editor will drop 412 role
+// assignments across 37 tenants". Empty dependents are skipped, and an empty
+// string is returned when nothing cascades.
+func DescribeCascade(action string, objectType string, key string, dependents ...string) string {
+	var parts []string
+	for _, dependent := range dependents {
+		if dependent != "" {
+			parts = append(parts, dependent)
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	joined := parts[0]
+	if len(parts) > 1 {
+		joined = strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+	}
+
+	return fmt.Sprintf("%s %s %s will drop %s", action, objectType, key, joined)
+}
+
+// AddCascadeWarning adds the warning built by DescribeCascade, if any.
+func AddCascadeWarning(diags *diag.Diagnostics, action string, objectType string, key string, dependents ...string) {
+	summary := DescribeCascade(action, objectType, key, dependents...)
+
+	if summary == "" {
+		return
+	}
+
+	diags.AddWarning(
+		"Dependent objects will be removed",
+		fmt.Sprintf("%s. Permit removes these together with the %s and they cannot be restored by reverting the configuration.", summary, objectType),
+	)
+}
+
+// AddCascadeLookupWarning reports that the dependents of an object could not
+// be listed. The plan itself is not blocked by this.
+func AddCascadeLookupWarning(diags *diag.Diagnostics, action string, objectType string, key string, err error) {
+	diags.AddWarning(
+		"Unable to list dependent objects",
+		fmt.Sprintf("Could not check what %s %s %s will remove in Permit: %s", action, objectType, key, err.Error()),
+	)
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "0 roles"},
+		{1, "1 role"},
+		{412, "412 roles"},
+	}
+
+	for _, tt := range tests {
+		if got := Pluralize(tt.count, "role"); got != tt.want {
+			t.Errorf("Pluralize(%d, %q) = %q, want %q", tt.count, "role", got, tt.want)
+		}
+	}
+}
+
+func TestDescribeRoleAssignments(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenants []string
+		want    string
+	}{
+		{"none", nil, ""},
+		{"single", []string{"acme"}, "1 role assignment across 1 tenant"},
+		{"shared tenant", []string{"acme", "acme", "globex"}, "3 role assignments across 2 tenants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var assignments []models.RoleAssignmentRead
+			for _, tenant := range tt.tenants {
+				assignments = append(assignments, models.RoleAssignmentRead{Tenant: tenant})
+			}
+
+			if got := DescribeRoleAssignments(assignments); got != tt.want {
+				t.Errorf("DescribeRoleAssignments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeCascade(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		dependents []string
+		want       string
+	}{
+		{"nothing", CascadeDelete, nil, ""},
+		{"only empty", CascadeDelete, []string{"", ""}, ""},
+		{"single", CascadeReplace, []string{"412 role assignments across 37 tenants"}, "replacing role editor will drop 412 role assignments across 37 tenants"},
+		{"two", CascadeDelete, []string{"2 role assignments across 1 tenant", "1 role derivation"}, "deleting role editor will drop 2 role assignments across 1 tenant and 1 role derivation"},
+		{"three skipping empty", CascadeDelete, []string{"3 roles", "", "1 relation", "5 resource instances"}, "deleting role editor will drop 3 roles, 1 relation and 5 resource instances"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeCascade(tt.action, "role", "editor", tt.dependents...); got != tt.want {
+				t.Errorf("DescribeCascade() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
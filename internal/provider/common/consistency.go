@@ -0,0 +1,173 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+const (
+	// NotFoundGracePeriod is how long after its creation an object that reads
+	// as "not found" is assumed to still be propagating rather than deleted.
+	NotFoundGracePeriod = 30 * time.Second
+
+	createdAtKey = "created_at"
+)
+
+// Polls start quickly, as most writes are readable within a second, and back
+// off up to maxConsistencyPollInterval.
+var (
+	minConsistencyPollInterval = 250 * time.Millisecond
+	maxConsistencyPollInterval = 5 * time.Second
+)
+
+type freshReadKey struct{}
+
+// FreshRead returns a context whose reads bypass the read cache, so that
+// polling observes every change the API makes visible.
+func FreshRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+func isFreshRead(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadKey{}).(bool)
+	return fresh
+}
+
+// WaitForConsistency polls check until it reports that a written object is
+// readable with the expected values. Reads that fail with "not found" are
+// polled again, any other error ends the wait. The wait is bounded by ctx,
+// which carries the operation timeout.
+func WaitForConsistency(ctx context.Context, object string, check func(ctx context.Context) (bool, error)) error {
+	return poll(FreshRead(ctx), object, check)
+}
+
+// poll calls check with a growing interval until it reports true, fails with
+// an error other than "not found", or ctx ends.
+func poll(ctx context.Context, object string, check func(ctx context.Context) (bool, error)) error {
+	interval := minConsistencyPollInterval
+
+	for attempt := 1; ; attempt++ {
+		consistent, err := check(ctx)
+
+		if err != nil && !IsNotFoundErr(err) {
+			return err
+		}
+
+		if err == nil && consistent {
+			if attempt > 1 {
+				tflog.Debug(ctx, "Write is visible", map[string]any{"object": object, "attempts": attempt})
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%s is not readable yet: %w", object, err)
+			}
+			return fmt.Errorf("%s does not reflect the write yet: %w", object, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		interval = min(interval*2, maxConsistencyPollInterval)
+	}
+}
+
+// AwaitWrite waits for a written object with WaitForConsistency when the
+// provider enables wait_for_consistency. The state is expected to be set
+// already, so that an object that never becomes consistent is kept as tainted.
+func AwaitWrite(ctx context.Context, object string, check func(ctx context.Context) (bool, error), diags *diag.Diagnostics) {
+	if !config.GetWaitForConsistency() {
+		return
+	}
+
+	if err := WaitForConsistency(ctx, object, check); err != nil {
+		diags.AddError(
+			"Unable to confirm "+object,
+			fmt.Sprintf("The %s was written, but Permit.io API did not return it with the configured values: %s", object, err),
+		)
+	}
+}
+
+// PrivateStateReader is implemented by the private state of read requests.
+type PrivateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// PrivateStateWriter is implemented by the private state of create responses.
+type PrivateStateWriter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// MarkCreated records in the private state of a resource when it was created,
+// for RetryRecentNotFound.
+func MarkCreated(ctx context.Context, private PrivateStateWriter, now time.Time) diag.Diagnostics {
+	value, err := json.Marshal(now.UTC().Format(time.RFC3339Nano))
+
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to record creation time", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, createdAtKey, value)
+}
+
+// createdAt returns the creation time recorded by MarkCreated, if any.
+func createdAt(ctx context.Context, private PrivateStateReader) (time.Time, bool) {
+	value, diags := private.GetKey(ctx, createdAtKey)
+
+	if diags.HasError() || len(value) == 0 {
+		return time.Time{}, false
+	}
+
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return time.Time{}, false
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, text)
+	return created, err == nil
+}
+
+// RetryRecentNotFound calls read, and calls it again for as long as it fails
+// with "not found" within NotFoundGracePeriod of the object's creation, so
+// that the Read right after a Create does not drop an object the API has not
+// made visible yet. Objects created earlier, or before their creation was
+// recorded, are read once.
+func RetryRecentNotFound(ctx context.Context, private PrivateStateReader, read func(ctx context.Context) error) error {
+	err := read(ctx)
+
+	if !IsNotFoundErr(err) {
+		return err
+	}
+
+	created, ok := createdAt(ctx, private)
+	remaining := time.Until(created.Add(NotFoundGracePeriod))
+
+	if !ok || remaining <= 0 {
+		return err
+	}
+
+	tflog.Debug(ctx, "Object created recently reads as not found, retrying", map[string]any{"remaining": remaining.String()})
+
+	waitCtx, cancel := context.WithTimeout(ctx, remaining)
+	defer cancel()
+
+	waitErr := WaitForConsistency(waitCtx, "the object", func(ctx context.Context) (bool, error) {
+		err = read(ctx)
+		return err == nil, err
+	})
+
+	if waitErr != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+type testPrivateState map[string][]byte
+
+func (s testPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return s[key], nil
+}
+
+func (s testPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	s[key] = value
+	return nil
+}
+
+func fastConsistencyPolls(t *testing.T) {
+	minInterval, maxInterval := minConsistencyPollInterval, maxConsistencyPollInterval
+	minConsistencyPollInterval, maxConsistencyPollInterval = time.Millisecond, time.Millisecond
+	t.Cleanup(func() {
+		minConsistencyPollInterval, maxConsistencyPollInterval = minInterval, maxInterval
+	})
+}
+
+func TestWaitForConsistency(t *testing.T) {
+	fastConsistencyPolls(t)
+	ctx := context.Background()
+	notFound := errors.New("404 Not Found")
+
+	t.Run("polls until consistent", func(t *testing.T) {
+		calls := 0
+		err := WaitForConsistency(ctx, "tenant", func(ctx context.Context) (bool, error) {
+			calls++
+			if !isFreshRead(ctx) {
+				t.Error("check context does not bypass the read cache")
+			}
+			switch calls {
+			case 1:
+				return false, notFound
+			case 2:
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("WaitForConsistency() = %v after %d calls, want nil after 3", err, calls)
+		}
+	})
+
+	t.Run("other errors end the wait", func(t *testing.T) {
+		failure := errors.New("403 Forbidden")
+		err := WaitForConsistency(ctx, "tenant", func(context.Context) (bool, error) {
+			return false, failure
+		})
+		if !errors.Is(err, failure) {
+			t.Errorf("WaitForConsistency() = %v, want %v", err, failure)
+		}
+	})
+
+	t.Run("bounded by the context", func(t *testing.T) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		err := WaitForConsistency(timeoutCtx, "tenant", func(context.Context) (bool, error) {
+			return false, notFound
+		})
+		if !errors.Is(err, notFound) {
+			t.Errorf("WaitForConsistency() = %v, want an error wrapping %v", err, notFound)
+		}
+	})
+}
+
+func TestRetryRecentNotFound(t *testing.T) {
+	fastConsistencyPolls(t)
+	ctx := context.Background()
+	notFound := errors.New("404 Not Found")
+
+	t.Run("recently created objects are read again", func(t *testing.T) {
+		private := testPrivateState{}
+		MarkCreated(ctx, private, time.Now())
+
+		calls := 0
+		err := RetryRecentNotFound(ctx, private, func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return notFound
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("RetryRecentNotFound() = %v after %d calls, want nil after 3", err, calls)
+		}
+	})
+
+	t.Run("older objects are read once", func(t *testing.T) {
+		private := testPrivateState{}
+		MarkCreated(ctx, private, time.Now().Add(-2*NotFoundGracePeriod))
+
+		calls := 0
+		err := RetryRecentNotFound(ctx, private, func(context.Context) error {
+			calls++
+			return notFound
+		})
+		if !errors.Is(err, notFound) || calls != 1 {
+			t.Errorf("RetryRecentNotFound() = %v after %d calls, want %v after 1", err, calls, notFound)
+		}
+	})
+
+	t.Run("objects without a creation time are read once", func(t *testing.T) {
+		calls := 0
+		err := RetryRecentNotFound(ctx, testPrivateState{}, func(context.Context) error {
+			calls++
+			return notFound
+		})
+		if !errors.Is(err, notFound) || calls != 1 {
+			t.Errorf("RetryRecentNotFound() = %v after %d calls, want %v after 1", err, calls, notFound)
+		}
+	})
+}
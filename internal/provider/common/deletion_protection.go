@@ -0,0 +1,37 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// CheckDeletionProtection reports whether the object described by state may be
+// deleted. When deletion_protection is enabled it adds an error diagnostic
+// explaining how to lift it and returns false.
+func CheckDeletionProtection(protection types.Bool, objectType string, key string, diags *diag.Diagnostics) bool {
+	if !protection.ValueBool() {
+		return true
+	}
+
+	diags.AddAttributeError(
+		path.Root("deletion_protection"),
+		"Deletion protection is enabled",
+		fmt.Sprintf("Cannot delete %s %q because deletion_protection is set to true. "+
+			"Deleting it would also delete everything that belongs to it in Permit. "+
+			"Set deletion_protection = false and apply before destroying or replacing it.", objectType, key),
+	)
+	return false
+}
+
+// DeletionProtectionFromState returns the deletion_protection value to keep in
+// state after a read. Imported objects have no prior value, so they get the
+// schema default instead of showing a diff on the next plan.
+func DeletionProtectionFromState(protection types.Bool) types.Bool {
+	if protection.IsNull() || protection.IsUnknown() {
+		return types.BoolValue(false)
+	}
+	return protection
+}
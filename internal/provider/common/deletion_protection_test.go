@@ -0,0 +1,54 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	tests := []struct {
+		name       string
+		protection types.Bool
+		want       bool
+	}{
+		{"enabled", types.BoolValue(true), false},
+		{"disabled", types.BoolValue(false), true},
+		// State written before the attribute existed.
+		{"null", types.BoolNull(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+
+			if got := CheckDeletionProtection(tt.protection, "tenant", "acme", &diags); got != tt.want {
+				t.Errorf("CheckDeletionProtection(%v) = %v, want %v", tt.protection, got, tt.want)
+			}
+			if diags.HasError() == tt.want {
+				t.Errorf("CheckDeletionProtection(%v) error diagnostics = %v, want %v", tt.protection, diags.HasError(), !tt.want)
+			}
+		})
+	}
+}
+
+func TestDeletionProtectionFromState(t *testing.T) {
+	tests := []struct {
+		name  string
+		state types.Bool
+		want  types.Bool
+	}{
+		{"imported", types.BoolNull(), types.BoolValue(false)},
+		{"enabled", types.BoolValue(true), types.BoolValue(true)},
+		{"disabled", types.BoolValue(false), types.BoolValue(false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeletionProtectionFromState(tt.state); !got.Equal(tt.want) {
+				t.Errorf("DeletionProtectionFromState(%v) = %v, want %v", tt.state, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package common
 
-import "strings"
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	permitErrors "github.com/permitio/permit-golang/pkg/errors"
+)
 
 // IsNotFoundErr reports whether err represents a "not found" response from the
 // Permit API. The API returns this in a few textual forms depending on the
@@ -11,3 +17,14 @@ func IsNotFoundErr(err error) bool {
 	}
 	return strings.Contains(strings.ToLower(err.Error()), "not found")
 }
+
+// IsConflictErr reports whether err represents a 409 response from the Permit
+// API, i.e. the object being created already exists. Only the SDK's own error
+// is trusted: a message merely mentioning a conflict is not one.
+func IsConflictErr(err error) bool {
+	var permitErr permitErrors.PermitError
+	if !errors.As(err, &permitErr) {
+		return false
+	}
+	return permitErr.StatusCode == http.StatusConflict || permitErr.ErrorCode == permitErrors.Conflict
+}
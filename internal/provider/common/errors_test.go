@@ -2,7 +2,13 @@ package common
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
+
+	permitErrors "github.com/permitio/permit-golang/pkg/errors"
 )
 
 func TestIsNotFoundErr(t *testing.T) {
@@ -26,3 +32,34 @@ func TestIsNotFoundErr(t *testing.T) {
 		})
 	}
 }
+
+func testResponse(status int) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}"))}
+}
+
+func TestIsConflictErr(t *testing.T) {
+	conflict := permitErrors.NewPermitConflictError(testResponse(http.StatusConflict))
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"api 409", conflict, true},
+		{"wrapped", fmt.Errorf("creating role: %w", conflict), true},
+		{"conflict code", permitErrors.NewPermitError(permitErrors.ConflictMessage, permitErrors.Conflict, permitErrors.API_ERROR, nil), true},
+		{"not found", permitErrors.NewPermitNotFoundError(nil, testResponse(http.StatusNotFound)), false},
+		// Text alone is not enough, e.g. a validation error naming a conflict.
+		{"message", errors.New("ErrorCode: Conflict, Message: The resource already exists"), false},
+		{"conflicting text", permitErrors.NewPermitUnprocessableEntityError(errors.New("conflicting permissions"), testResponse(http.StatusUnprocessableEntity)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConflictErr(tt.err); got != tt.want {
+				t.Errorf("IsConflictErr(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+const (
+	// KeyMaxLength is the longest key Permit accepts.
+	KeyMaxLength = 255
+
+	// PermissionWildcard matches every resource or every action in a
+	// permission pattern, as in "document:*" or "*:read".
+	PermissionWildcard = "*"
+)
+
+// KeyPattern is the format of the keys of resources, actions, attributes,
+// roles, tenants, condition sets, relations and proxy configs, which Permit
+// requires to be URL-friendly.
+var KeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ReservedKeys are the keys of the resources Permit defines for users and
+// tenants, which can't be used for the objects Terraform creates.
+var ReservedKeys = []string{"__user", "__tenant"}
+
+// KeyFormatValidators check that a string references a key in the format
+// Permit accepts. They are meant for attributes that refer to an existing
+// object, which may be a built-in one.
+func KeyFormatValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.LengthBetween(1, KeyMaxLength),
+		stringvalidator.RegexMatches(KeyPattern, "must only contain letters, digits, \"-\" and \"_\""),
+	}
+}
+
+// KeyValidators check the key of an object Terraform creates: its format and
+// that it is not reserved by Permit.
+func KeyValidators() []validator.String {
+	return append(KeyFormatValidators(), reservedKeyValidator{})
+}
+
+// FreeFormKeyValidators check the keys Permit accepts in any format, such as
+// user and resource instance keys, which often are emails or IDs of other
+// systems.
+func FreeFormKeyValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.LengthBetween(1, KeyMaxLength),
+	}
+}
+
+type reservedKeyValidator struct{}
+
+func (v reservedKeyValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("must not be one of the keys reserved by Permit: %s", strings.Join(ReservedKeys, ", "))
+}
+
+func (v reservedKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v reservedKeyValidator) ValidateString(_ context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsUnknown() || request.ConfigValue.IsNull() {
+		return
+	}
+
+	if value := request.ConfigValue.ValueString(); slices.Contains(ReservedKeys, value) {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Reserved key",
+			fmt.Sprintf("The key %q is reserved by Permit for its built-in resources. Choose a different key.", value),
+		)
+	}
+}
+
+// PermissionValidator checks that a string is a permission in the
+// "resource:action" format, or a single action key or ID, which the API
+// resolves on its own.
+func PermissionValidator() validator.String {
+	return permissionValidator{}
+}
+
+// RolePermissionValidator checks the permissions of a role, which may also use
+// the "*" wildcard for either part of "resource:action".
+func RolePermissionValidator() validator.String {
+	return permissionValidator{allowWildcard: true}
+}
+
+type permissionValidator struct {
+	allowWildcard bool
+}
+
+func (v permissionValidator) Description(_ context.Context) string {
+	description := "must be a permission in the format resource:action"
+	if v.allowWildcard {
+		description += `, where either part may be "*"`
+	}
+	return description + ", or an action key"
+}
+
+func (v permissionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v permissionValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsUnknown() || request.ConfigValue.IsNull() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if v.valid(value) {
+		return
+	}
+
+	response.Diagnostics.AddAttributeError(
+		request.Path,
+		"Invalid permission",
+		fmt.Sprintf("The permission %q %s.", value, v.Description(ctx)),
+	)
+}
+
+func (v permissionValidator) valid(permission string) bool {
+	resource, action, found := strings.Cut(permission, ":")
+	if !found {
+		return v.validPart(permission, false)
+	}
+
+	return v.validPart(resource, v.allowWildcard) && v.validPart(action, v.allowWildcard)
+}
+
+func (v permissionValidator) validPart(part string, allowWildcard bool) bool {
+	if allowWildcard && part == PermissionWildcard {
+		return true
+	}
+	return len(part) <= KeyMaxLength && KeyPattern.MatchString(part)
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// validateString runs validators on value and reports whether any of them
+// failed.
+func validateString(validators []validator.String, value types.String) bool {
+	request := validator.StringRequest{Path: path.Root("key"), ConfigValue: value}
+	response := &validator.StringResponse{}
+
+	for _, v := range validators {
+		v.ValidateString(context.Background(), request, response)
+	}
+
+	return response.Diagnostics.HasError()
+}
+
+func TestKeyValidators(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         types.String
+		wantErr       bool
+		wantFormatErr bool
+		wantFreeErr   bool
+	}{
+		{"slug", types.StringValue("document"), false, false, false},
+		{"dashes and underscores", types.StringValue("Doc_type-2"), false, false, false},
+		{"null", types.StringNull(), false, false, false},
+		{"unknown", types.StringUnknown(), false, false, false},
+		{"empty", types.StringValue(""), true, true, true},
+		{"space", types.StringValue("my document"), true, true, false},
+		{"colon", types.StringValue("document:read"), true, true, false},
+		{"email", types.StringValue("john@example.com"), true, true, false},
+		{"longest", types.StringValue(strings.Repeat("a", KeyMaxLength)), false, false, false},
+		{"too long", types.StringValue(strings.Repeat("a", KeyMaxLength+1)), true, true, true},
+		{"reserved user", types.StringValue("__user"), true, false, false},
+		{"reserved tenant", types.StringValue("__tenant"), true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateString(KeyValidators(), tt.value); got != tt.wantErr {
+				t.Errorf("KeyValidators(%v) error = %v, want %v", tt.value, got, tt.wantErr)
+			}
+			if got := validateString(KeyFormatValidators(), tt.value); got != tt.wantFormatErr {
+				t.Errorf("KeyFormatValidators(%v) error = %v, want %v", tt.value, got, tt.wantFormatErr)
+			}
+			if got := validateString(FreeFormKeyValidators(), tt.value); got != tt.wantFreeErr {
+				t.Errorf("FreeFormKeyValidators(%v) error = %v, want %v", tt.value, got, tt.wantFreeErr)
+			}
+		})
+	}
+}
+
+func TestPermissionValidators(t *testing.T) {
+	tests := []struct {
+		permission  string
+		wantErr     bool
+		wantRoleErr bool
+	}{
+		{"document:read", false, false},
+		{"doc-type:read_all", false, false},
+		{"read", false, false},
+		{"3f1c6f1e-6b4a-4d2e-9c1a-2b7d9e0f5a61", false, false},
+		{"document:*", true, false},
+		{"*:read", true, false},
+		{"*:*", true, false},
+		{"*", true, true},
+		{"document:", true, true},
+		{":read", true, true},
+		{"document:read:all", true, true},
+		{"my document:read", true, true},
+		{"", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission, func(t *testing.T) {
+			value := types.StringValue(tt.permission)
+			if got := validateString([]validator.String{PermissionValidator()}, value); got != tt.wantErr {
+				t.Errorf("PermissionValidator(%q) error = %v, want %v", tt.permission, got, tt.wantErr)
+			}
+			if got := validateString([]validator.String{RolePermissionValidator()}, value); got != tt.wantRoleErr {
+				t.Errorf("RolePermissionValidator(%q) error = %v, want %v", tt.permission, got, tt.wantRoleErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// KeyedMutex serializes work per key while letting different keys proceed
+// concurrently. Locks are dropped once nobody holds or waits on them.
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock is held by whoever put a value in slot, so that waiting for it can
+// be given up when the context is done.
+type keyedLock struct {
+	slot chan struct{}
+	refs int
+}
+
+// Lock locks key and returns the function that unlocks it, or the error of
+// ctx when it is done before the lock is free.
+func (m *KeyedMutex) Lock(ctx context.Context, key string) (func(), error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[string]*keyedLock{}
+	}
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyedLock{slot: make(chan struct{}, 1)}
+		m.locks[key] = lock
+	}
+	lock.refs++
+	m.mu.Unlock()
+
+	select {
+	case lock.slot <- struct{}{}:
+	default:
+		tflog.Debug(ctx, "Waiting for another write to the same object", map[string]any{"lock": key})
+
+		select {
+		case lock.slot <- struct{}{}:
+		case <-ctx.Done():
+			m.release(key, lock)
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		<-lock.slot
+		m.release(key, lock)
+	}, nil
+}
+
+func (m *KeyedMutex) release(key string, lock *keyedLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock.refs--
+	if lock.refs == 0 {
+		delete(m.locks, key)
+	}
+}
+
+// parentLocks is shared by every resource of the provider.
+var parentLocks KeyedMutex
+
+// LockResource serializes writes that rewrite a resource or one of its
+// children, such as its actions, attributes, roles and relations, so that
+// sibling changes applied in parallel do not overwrite each other.
+func LockResource(ctx context.Context, resourceKey string) (func(), error) {
+	return parentLocks.Lock(ctx, "resource/"+resourceKey)
+}
+
+// LockRole serializes writes to a role, such as creating, updating or deleting
+// it and the derivations granting it, given the resource of a resource role or an
+// empty resource for a top-level role.
+func LockRole(ctx context.Context, resourceKey string, roleKey string) (func(), error) {
+	return parentLocks.Lock(ctx, "role/"+RoleNodeId(resourceKey, roleKey))
+}
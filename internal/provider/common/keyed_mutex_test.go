@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	ctx := context.Background()
+	var m KeyedMutex
+	var inFlight, maxInFlight int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := m.Lock(ctx, "resource/document")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+
+			if current := atomic.AddInt64(&inFlight, 1); current > atomic.LoadInt64(&maxInFlight) {
+				atomic.StoreInt64(&maxInFlight, current)
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt64(&inFlight, -1)
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 1 {
+		t.Errorf("holders of the same key at once = %d, want 1", maxInFlight)
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("locks left = %d, want 0", len(m.locks))
+	}
+}
+
+func TestKeyedMutexOtherKeys(t *testing.T) {
+	ctx := context.Background()
+	var m KeyedMutex
+
+	unlock, _ := m.Lock(ctx, "resource/document")
+	defer unlock()
+
+	done := make(chan struct{})
+	go func() {
+		unlockFolder, _ := m.Lock(ctx, "resource/folder")
+		unlockFolder()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("locking another key waited on a held key")
+	}
+}
+
+func TestKeyedMutexCancelled(t *testing.T) {
+	var m KeyedMutex
+
+	unlock, _ := m.Lock(context.Background(), "role/document#editor")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := m.Lock(ctx, "role/document#editor"); err == nil {
+		t.Fatal("Lock() error = nil, want the context error")
+	}
+
+	unlock()
+
+	if len(m.locks) != 0 {
+		t.Errorf("locks left = %d, want 0", len(m.locks))
+	}
+}
@@ -0,0 +1,151 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Endpoint families with a concurrency budget of their own, on top of the
+// overall limit.
+const (
+	RequestFamilySchemaWrites     = "schema_writes"
+	RequestFamilyAssignmentWrites = "assignment_writes"
+)
+
+// RequestFamily returns the endpoint family of a Permit API request, or an
+// empty string for requests that only count against the overall limit.
+func RequestFamily(method string, urlPath string) string {
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return ""
+	}
+
+	if strings.Contains(urlPath, "/role_assignments") ||
+		strings.Contains(urlPath, "/relationship_tuples") ||
+		strings.HasSuffix(strings.TrimSuffix(urlPath, "/"), "/roles") && (strings.Contains(urlPath, "/users/") || strings.Contains(urlPath, "/groups/")) {
+		return RequestFamilyAssignmentWrites
+	}
+
+	if strings.Contains(urlPath, "/v2/schema/") {
+		return RequestFamilySchemaWrites
+	}
+
+	return ""
+}
+
+// LimitedTransport is an http.RoundTripper that caps the number of Permit API
+// requests in flight, overall and per endpoint family. It is shared by every
+// resource and data source, as they all use the client created when the
+// provider is configured.
+//
+// It also applies the per-request timeout, which only starts once a request
+// holds its slots, so the time spent queued behind other requests does not
+// count against it. The client using it must not set http.Client.Timeout.
+type LimitedTransport struct {
+	base     http.RoundTripper
+	timeout  time.Duration
+	all      chan struct{}
+	families map[string]chan struct{}
+}
+
+// NewLimitedTransport wraps base so that at most maxConcurrent requests are in
+// flight, and at most budgets[family] requests of each family, each of which
+// may take up to timeout once sent. Limits and timeouts of zero or less are
+// not enforced.
+func NewLimitedTransport(base http.RoundTripper, maxConcurrent int64, budgets map[string]int64, timeout time.Duration) *LimitedTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t := &LimitedTransport{base: base, timeout: timeout, families: map[string]chan struct{}{}}
+
+	if maxConcurrent > 0 {
+		t.all = make(chan struct{}, maxConcurrent)
+	}
+
+	for family, budget := range budgets {
+		if budget > 0 {
+			t.families[family] = make(chan struct{}, budget)
+		}
+	}
+
+	return t
+}
+
+// RoundTrip implements http.RoundTripper. The family slot is always taken
+// before the overall one, so that requests waiting on a family budget do not
+// hold back requests of other families.
+func (t *LimitedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	family := RequestFamily(request.Method, request.URL.Path)
+
+	release, err := t.acquire(request, family, t.families[family])
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	releaseAll, err := t.acquire(request, "all", t.all)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseAll()
+
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(request)
+	}
+
+	ctx, cancel := context.WithTimeout(request.Context(), t.timeout)
+	response, err := t.base.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	response.Body = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+// cancelOnClose keeps the timeout of a request running while its response
+// body is read, and releases it once the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func (t *LimitedTransport) acquire(request *http.Request, limit string, slots chan struct{}) (func(), error) {
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	ctx := request.Context()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+	}
+
+	tflog.Debug(ctx, "Permit.io API request queued", map[string]any{
+		"method": request.Method,
+		"path":   request.URL.Path,
+		"limit":  limit,
+		"slots":  cap(slots),
+	})
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
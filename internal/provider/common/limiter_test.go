@@ -0,0 +1,132 @@
+package common
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestFamily(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/v2/schema/p/e/resources", ""},
+		{"POST", "/v2/schema/p/e/resources", RequestFamilySchemaWrites},
+		{"PATCH", "/v2/schema/p/e/resources/document/roles/editor", RequestFamilySchemaWrites},
+		{"POST", "/v2/facts/p/e/role_assignments", RequestFamilyAssignmentWrites},
+		{"DELETE", "/v2/facts/p/e/role_assignments/bulk", RequestFamilyAssignmentWrites},
+		{"POST", "/v2/facts/p/e/relationship_tuples", RequestFamilyAssignmentWrites},
+		{"POST", "/v2/facts/p/e/users/alice/roles", RequestFamilyAssignmentWrites},
+		{"POST", "/v2/schema/p/e/groups/admins/roles", RequestFamilyAssignmentWrites},
+		{"POST", "/v2/facts/p/e/tenants", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RequestFamily(tt.method, tt.path); got != tt.want {
+			t.Errorf("RequestFamily(%q, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLimitedTransport(t *testing.T) {
+	var inFlight, maxInFlight int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt64(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt64(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt64(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt64(&inFlight, -1)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		max     int64
+		budgets map[string]int64
+		path    string
+		want    int64
+	}{
+		{"overall limit", 3, nil, "/v2/schema/p/e/resources", 3},
+		{"family budget", 3, map[string]int64{RequestFamilySchemaWrites: 1}, "/v2/schema/p/e/resources", 1},
+		{"other family", 3, map[string]int64{RequestFamilyAssignmentWrites: 1}, "/v2/schema/p/e/resources", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt64(&maxInFlight, 0)
+			client := &http.Client{Transport: NewLimitedTransport(nil, tt.max, tt.budgets, 0)}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					resp, err := client.Post(server.URL+tt.path, "application/json", nil)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp.Body.Close()
+				}()
+			}
+			wg.Wait()
+
+			if got := atomic.LoadInt64(&maxInFlight); got > tt.want {
+				t.Errorf("max requests in flight = %d, want at most %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitedTransportTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		} else {
+			time.Sleep(40 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewLimitedTransport(nil, 1, nil, 100*time.Millisecond)}
+
+	t.Run("queued time does not count", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.Get(server.URL + "/fast")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := io.ReadAll(resp.Body); err != nil {
+					t.Error(err)
+				}
+				resp.Body.Close()
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("slow request times out", func(t *testing.T) {
+		resp, err := client.Get(server.URL + "/slow")
+		if err == nil {
+			resp.Body.Close()
+			t.Fatal("expected the request to time out")
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+// ListPageSize is the largest page size the Permit API accepts on list endpoints.
+const ListPageSize = 100
+
+// ListAllPages calls fetch for consecutive pages until a short page is
+// returned, and collects every item.
+func ListAllPages[T any](fetch func(page int, perPage int) ([]T, error)) ([]T, error) {
+	var all []T
+
+	for page := 1; ; page++ {
+		items, err := fetch(page, ListPageSize)
+
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+
+		if len(items) < ListPageSize {
+			return all, nil
+		}
+	}
+}
+
+// ListAllRoleAssignments returns every role assignment matching the given
+// filters. Empty filters are ignored.
+func ListAllRoleAssignments(ctx context.Context, client *permit.Client, user string, role string, tenant string) ([]models.RoleAssignmentRead, error) {
+	return ListAllPages(func(page int, perPage int) ([]models.RoleAssignmentRead, error) {
+		assignments, err := client.Api.RoleAssignments.List(ctx, page, perPage, user, role, tenant)
+
+		if err != nil || assignments == nil {
+			return nil, err
+		}
+
+		return *assignments, nil
+	})
+}
+
+// IsBuiltinResource reports whether key is a resource defined by Permit itself,
+// such as __user and __tenant.
+func IsBuiltinResource(key string) bool {
+	return strings.HasPrefix(key, "__")
+}
+
+// ResourceListOptions selects what ListResources reads for each resource
+// besides the resource itself.
+type ResourceListOptions struct {
+	Roles     bool
+	Relations bool
+}
+
+// ResourceListing is a resource with the roles and relations ListResources was
+// asked to read, each sorted by key.
+type ResourceListing struct {
+	models.ResourceRead
+
+	Roles     []models.ResourceRoleRead
+	Relations []models.RelationRead
+}
+
+// ListResources returns every resource defined in the environment, sorted by
+// key. Built-in resources are left out, since they are not created by users.
+func ListResources(ctx context.Context, client *permit.Client, options ResourceListOptions) ([]ResourceListing, error) {
+	resources, err := ListAllPages(func(page int, perPage int) ([]models.ResourceRead, error) {
+		return client.Api.Resources.List(ctx, page, perPage)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed listing resources: %w", err)
+	}
+
+	var listings []ResourceListing
+
+	for _, resource := range resources {
+		if IsBuiltinResource(resource.Key) {
+			continue
+		}
+
+		listing := ResourceListing{ResourceRead: resource}
+
+		if options.Roles {
+			listing.Roles, err = ListAllPages(func(page int, perPage int) ([]models.ResourceRoleRead, error) {
+				roles, err := client.Api.ResourceRoles.List(ctx, page, perPage, resource.Key)
+
+				if err != nil || roles == nil {
+					return nil, err
+				}
+
+				return *roles, nil
+			})
+
+			if err != nil {
+				return nil, fmt.Errorf("failed listing roles of resource %s: %w", resource.Key, err)
+			}
+
+			sort.Slice(listing.Roles, func(i, j int) bool { return listing.Roles[i].Key < listing.Roles[j].Key })
+		}
+
+		if options.Relations {
+			listing.Relations, err = ListAllPages(func(page int, perPage int) ([]models.RelationRead, error) {
+				relations, err := client.Api.ResourceRelations.List(ctx, page, perPage, resource.Key)
+
+				if err != nil || relations == nil {
+					return nil, err
+				}
+
+				return *relations, nil
+			})
+
+			if err != nil {
+				return nil, fmt.Errorf("failed listing relations of resource %s: %w", resource.Key, err)
+			}
+
+			sort.Slice(listing.Relations, func(i, j int) bool { return listing.Relations[i].Key < listing.Relations[j].Key })
+		}
+
+		listings = append(listings, listing)
+	}
+
+	sort.Slice(listings, func(i, j int) bool { return listings[i].Key < listings[j].Key })
+
+	return listings, nil
+}
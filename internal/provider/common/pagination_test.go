@@ -0,0 +1,58 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestListAllPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		wantCalls int
+	}{
+		{"empty", 0, 1},
+		{"short page", 42, 1},
+		{"exactly one page", ListPageSize, 2},
+		{"several pages", 2*ListPageSize + 7, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			got, err := ListAllPages(func(page int, perPage int) ([]int, error) {
+				calls++
+				var items []int
+				for i := (page - 1) * perPage; i < tt.total && i < page*perPage; i++ {
+					items = append(items, i)
+				}
+				return items, nil
+			})
+
+			if err != nil {
+				t.Fatalf("ListAllPages() error = %v", err)
+			}
+			if len(got) != tt.total {
+				t.Errorf("ListAllPages() returned %d items, want %d", len(got), tt.total)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("ListAllPages() made %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestListAllPagesError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	_, err := ListAllPages(func(page int, perPage int) ([]int, error) {
+		if page == 2 {
+			return nil, wantErr
+		}
+		return make([]int, perPage), nil
+	})
+
+	if !errors.Is(err, wantErr) {
+		t.Errorf("ListAllPages() error = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,288 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+// pdpRequestTimeout bounds each request to the PDP while polling it.
+const pdpRequestTimeout = 10 * time.Second
+
+// pdpOpaPort is where PDP containers serve their OPA, used when the
+// wait_for_pdp block has no opa_url.
+const pdpOpaPort = "8181"
+
+// pdpHTTPClient sends the requests to the PDP. They must not go through the
+// client of the Permit API, whose read cache would answer polls with earlier
+// responses and whose limits are meant for the API.
+var pdpHTTPClient = &http.Client{Timeout: pdpRequestTimeout}
+
+// PDPClient queries the PDP configured in the provider wait_for_pdp block.
+type PDPClient struct {
+	url        string
+	opaUrl     string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type pdpUserPermissionsRequest struct {
+	User struct {
+		Key string `json:"key"`
+	} `json:"user"`
+	Tenants   []string `json:"tenants"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// pdpUserPermissions is one entry of a /user-permissions response, describing
+// the roles of the user in a tenant, or on a resource instance when Resource
+// is set.
+type pdpUserPermissions struct {
+	Tenant struct {
+		Key string `json:"key"`
+	} `json:"tenant"`
+	Resource *struct {
+		Type string `json:"type"`
+		Key  string `json:"key"`
+	} `json:"resource"`
+	Roles []string `json:"roles"`
+}
+
+// HasRole reports whether the PDP grants user the role in tenant, or on the
+// resource instance when it is set as "resource:instance".
+func (c *PDPClient) HasRole(ctx context.Context, user, tenant, resourceInstance, role string) (bool, error) {
+	roles, err := c.UserRoles(ctx, user, tenant, resourceInstance)
+	return slices.Contains(roles, role), err
+}
+
+// UserRoles returns the roles the PDP grants user in tenant, or on the
+// resource instance when it is set as "resource:instance".
+func (c *PDPClient) UserRoles(ctx context.Context, user, tenant, resourceInstance string) ([]string, error) {
+	request := pdpUserPermissionsRequest{Tenants: []string{tenant}}
+	request.User.Key = user
+	if resourceInstance != "" {
+		request.Resources = []string{resourceInstance}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/user-permissions", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PDP request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var permissions map[string]pdpUserPermissions
+	if err := json.Unmarshal(respBody, &permissions); err != nil {
+		return nil, fmt.Errorf("failed to parse PDP response: %w", err)
+	}
+
+	for _, entry := range permissions {
+		if entry.Tenant.Key != tenant {
+			continue
+		}
+
+		if resourceInstance == "" && entry.Resource == nil {
+			return entry.Roles, nil
+		}
+
+		if entry.Resource != nil && entry.Resource.Type+":"+entry.Resource.Key == resourceInstance {
+			return entry.Roles, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PolicyVersion returns a fingerprint of the policy data loaded into the OPA
+// of the PDP. It changes whenever the PDP loads a policy update, so comparing
+// it with the one read before a write tells whether the PDP has the new policy.
+func (c *PDPClient) PolicyVersion(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opaUrl+"/v1/data", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OPA request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var data struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &data); err != nil {
+		return "", fmt.Errorf("failed to parse OPA response: %w", err)
+	}
+
+	sum := sha256.Sum256(data.Result)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// pdpOpaUrl returns the OPA that policy versions are read from: the opa_url of
+// the wait_for_pdp block, or port 8181 on the host of the PDP.
+func pdpOpaUrl(pdpUrl string) string {
+	if opaUrl := config.GetWaitForPDPOpaUrl(); opaUrl != "" {
+		return strings.TrimSuffix(opaUrl, "/")
+	}
+
+	parsed, err := url.Parse(pdpUrl)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + net.JoinHostPort(parsed.Hostname(), pdpOpaPort)
+}
+
+// newPDPClient returns a client for the PDP of the provider-level wait_for_pdp
+// block and the wait_for_pdp timeout, or adds an error and returns nil when
+// the block is not configured.
+func newPDPClient(object string, diags *diag.Diagnostics) (*PDPClient, time.Duration) {
+	pdpUrl, timeout := config.GetWaitForPDP()
+	if pdpUrl == "" {
+		diags.AddError(
+			"PDP is not configured",
+			fmt.Sprintf("The %s sets wait_for_pdp, but the provider has no wait_for_pdp block with the URL of the PDP to wait for.", object),
+		)
+		return nil, 0
+	}
+
+	return &PDPClient{
+		url:        strings.TrimSuffix(pdpUrl, "/"),
+		opaUrl:     pdpOpaUrl(pdpUrl),
+		apiKey:     config.GetGlobalApiKey(),
+		httpClient: pdpHTTPClient,
+	}, timeout
+}
+
+// AwaitPDP polls the PDP of the provider-level wait_for_pdp block until check
+// reports that it reflects a write, when the resource opts in with its own
+// wait_for_pdp attribute. PDPs receive policy updates asynchronously, so this
+// lets the apply finish only once the PDP decides with the new policy. The wait
+// is bounded by the wait_for_pdp timeout and by ctx.
+func AwaitPDP(ctx context.Context, object string, enabled types.Bool, check func(ctx context.Context, pdp *PDPClient) (bool, error), diags *diag.Diagnostics) {
+	if !enabled.ValueBool() {
+		return
+	}
+
+	pdp, timeout := newPDPClient(object, diags)
+	if pdp == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := poll(ctx, object, func(ctx context.Context) (bool, error) {
+		return check(ctx, pdp)
+	})
+	if err != nil {
+		diags.AddError(
+			"Unable to confirm "+object+" in the PDP",
+			fmt.Sprintf("The %s was written, but the PDP at %s did not reflect it: %s", object, pdp.url, err),
+		)
+	}
+}
+
+// PolicyWait waits for the PDP to load the policy written by a policy resource,
+// such as a role, a resource or a condition set rule. Whether a PDP decides
+// with such a write cannot be checked for one user, so it compares the policy
+// version the PDP holds with the one it held before the write.
+type PolicyWait struct {
+	object  string
+	pdp     *PDPClient
+	timeout time.Duration
+	version string
+}
+
+// StartPolicyWait reads the policy version the PDP holds before a policy write,
+// when the resource opts in with its own wait_for_pdp attribute. It returns nil
+// when it does not, or when the version cannot be read, which adds an error to
+// diags so the write is not made.
+func StartPolicyWait(ctx context.Context, object string, enabled types.Bool, diags *diag.Diagnostics) *PolicyWait {
+	if !enabled.ValueBool() {
+		return nil
+	}
+
+	pdp, timeout := newPDPClient(object, diags)
+	if pdp == nil {
+		return nil
+	}
+
+	version, err := pdp.PolicyVersion(ctx)
+	if err != nil {
+		diags.AddError(
+			"Unable to read the policy version of the PDP",
+			fmt.Sprintf("The %s sets wait_for_pdp, but the policy version could not be read from %s: %s", object, pdp.opaUrl, err),
+		)
+		return nil
+	}
+
+	return &PolicyWait{object: object, pdp: pdp, timeout: timeout, version: version}
+}
+
+// Await polls the PDP until it holds a policy version other than the one read
+// by StartPolicyWait. It does nothing on a nil PolicyWait. The wait is bounded
+// by the wait_for_pdp timeout and by ctx.
+func (w *PolicyWait) Await(ctx context.Context, diags *diag.Diagnostics) {
+	if w == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	err := poll(ctx, w.object, func(ctx context.Context) (bool, error) {
+		version, err := w.pdp.PolicyVersion(ctx)
+		return err == nil && version != w.version, err
+	})
+	if err != nil {
+		diags.AddError(
+			"Unable to confirm "+w.object+" in the PDP",
+			fmt.Sprintf("The %s was written, but the PDP at %s did not load a new policy version: %s", w.object, w.pdp.url, err),
+		)
+	}
+}
@@ -0,0 +1,214 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+const testUserPermissions = `{
+	"__tenant:default": {"tenant": {"key": "default"}, "permissions": ["document:read"], "roles": ["viewer"]},
+	"document:doc-1": {"tenant": {"key": "default"}, "resource": {"type": "document", "key": "doc-1"}, "permissions": ["document:write"], "roles": ["editor"]}
+}`
+
+func withPDP(t *testing.T, url string) {
+	config.SetGlobalConfig("", "test-key")
+	config.SetWaitForPDP(url, time.Second)
+	t.Cleanup(func() {
+		config.SetGlobalConfig("", "")
+		config.SetWaitForPDP("", 0)
+	})
+}
+
+func TestPDPClientUserRoles(t *testing.T) {
+	var request pdpUserPermissionsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user-permissions" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		_, _ = w.Write([]byte(testUserPermissions))
+	}))
+	defer server.Close()
+
+	pdp := &PDPClient{url: server.URL, apiKey: "test-key", httpClient: server.Client()}
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		tenant           string
+		resourceInstance string
+		want             []string
+	}{
+		{"tenant roles", "default", "", []string{"viewer"}},
+		{"resource instance roles", "default", "document:doc-1", []string{"editor"}},
+		{"other tenant", "other", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles, err := pdp.UserRoles(ctx, "alice", tt.tenant, tt.resourceInstance)
+			if err != nil {
+				t.Fatalf("UserRoles() error = %v", err)
+			}
+			if !reflect.DeepEqual(roles, tt.want) {
+				t.Errorf("UserRoles() = %v, want %v", roles, tt.want)
+			}
+			if request.User.Key != "alice" || request.Tenants[0] != tt.tenant {
+				t.Errorf("request = %+v, want user alice in tenant %s", request, tt.tenant)
+			}
+		})
+	}
+}
+
+func TestAwaitPDP(t *testing.T) {
+	fastConsistencyPolls(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(testUserPermissions))
+	}))
+	defer server.Close()
+
+	hasViewer := func(ctx context.Context, pdp *PDPClient) (bool, error) {
+		return pdp.HasRole(ctx, "alice", "default", "", "viewer")
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var diags diag.Diagnostics
+		AwaitPDP(ctx, "role assignment", types.BoolNull(), hasViewer, &diags)
+		if diags.HasError() || calls.Load() != 0 {
+			t.Errorf("AwaitPDP() = %v after %d calls, want no calls", diags, calls.Load())
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		var diags diag.Diagnostics
+		AwaitPDP(ctx, "role assignment", types.BoolValue(true), hasViewer, &diags)
+		if !diags.HasError() {
+			t.Error("AwaitPDP() without a wait_for_pdp block should fail")
+		}
+	})
+
+	t.Run("polls until the PDP reflects the write", func(t *testing.T) {
+		withPDP(t, server.URL+"/")
+		var diags diag.Diagnostics
+		AwaitPDP(ctx, "role assignment", types.BoolValue(true), hasViewer, &diags)
+		if diags.HasError() || calls.Load() != 3 {
+			t.Errorf("AwaitPDP() = %v after %d calls, want success after 3", diags, calls.Load())
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		withPDP(t, server.URL)
+		config.SetWaitForPDP(server.URL, 10*time.Millisecond)
+		var diags diag.Diagnostics
+		AwaitPDP(ctx, "role assignment", types.BoolValue(true), func(ctx context.Context, pdp *PDPClient) (bool, error) {
+			return pdp.HasRole(ctx, "alice", "default", "", "admin")
+		}, &diags)
+		if !diags.HasError() {
+			t.Error("AwaitPDP() should fail when the PDP never reflects the write")
+		}
+	})
+}
+
+func TestPDPOpaUrl(t *testing.T) {
+	tests := []struct {
+		name   string
+		pdpUrl string
+		opaUrl string
+		want   string
+	}{
+		{"port 8181 on the PDP host", "http://localhost:7766", "", "http://localhost:8181"},
+		{"PDP without a port", "https://pdp.example.com/", "", "https://pdp.example.com:8181"},
+		{"configured opa_url", "http://localhost:7766", "http://opa:8181/", "http://opa:8181"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetWaitForPDPOpaUrl(tt.opaUrl)
+			t.Cleanup(func() { config.SetWaitForPDPOpaUrl("") })
+
+			if got := pdpOpaUrl(tt.pdpUrl); got != tt.want {
+				t.Errorf("pdpOpaUrl(%q) = %q, want %q", tt.pdpUrl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyWait(t *testing.T) {
+	fastConsistencyPolls(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/data" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if calls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"result": {"roles": {"viewer": {}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result": {"roles": {"viewer": {}, "editor": {}}}}`))
+	}))
+	defer server.Close()
+
+	withOpa := func(t *testing.T) {
+		withPDP(t, "http://localhost:7766")
+		config.SetWaitForPDPOpaUrl(server.URL)
+		t.Cleanup(func() { config.SetWaitForPDPOpaUrl("") })
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var diags diag.Diagnostics
+		wait := StartPolicyWait(ctx, "role", types.BoolValue(false), &diags)
+		wait.Await(ctx, &diags)
+		if wait != nil || diags.HasError() || calls.Load() != 0 {
+			t.Errorf("PolicyWait = %v after %d calls, want no calls", diags, calls.Load())
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		var diags diag.Diagnostics
+		if StartPolicyWait(ctx, "role", types.BoolValue(true), &diags) != nil || !diags.HasError() {
+			t.Error("StartPolicyWait() without a wait_for_pdp block should fail")
+		}
+	})
+
+	t.Run("polls until the PDP loads a new policy version", func(t *testing.T) {
+		withOpa(t)
+		var diags diag.Diagnostics
+		wait := StartPolicyWait(ctx, "role", types.BoolValue(true), &diags)
+		wait.Await(ctx, &diags)
+		if diags.HasError() || calls.Load() != 3 {
+			t.Errorf("PolicyWait = %v after %d calls, want success after 3", diags, calls.Load())
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		withOpa(t)
+		config.SetWaitForPDP("http://localhost:7766", 10*time.Millisecond)
+		var diags diag.Diagnostics
+		wait := StartPolicyWait(ctx, "role", types.BoolValue(true), &diags)
+		wait.Await(ctx, &diags)
+		if !diags.HasError() {
+			t.Error("PolicyWait should fail when the PDP never loads a new policy version")
+		}
+	})
+}
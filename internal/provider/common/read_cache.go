@@ -0,0 +1,202 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+)
+
+// CachingTransport is an http.RoundTripper that caches successful GET responses
+// of the Permit API for a short time, and makes concurrent identical GETs share
+// a single request. Many resources read the same parent objects during plan and
+// refresh, which would otherwise each cost a request.
+//
+// A write invalidates every cached response of the objects it touches: the
+// written path, the paths beneath it and the collections above it.
+type CachingTransport struct {
+	base  http.RoundTripper
+	ttl   time.Duration
+	now   func() time.Time
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+	// generation is bumped on every write, so that a GET that started before
+	// the write does not store a stale response.
+	generation uint64
+}
+
+type cachedResponse struct {
+	path    string
+	expires time.Time
+	status  int
+	header  http.Header
+	body    []byte
+}
+
+// NewCachingTransport wraps base with a read cache whose entries live for ttl.
+// A ttl of zero or less disables caching but keeps requests coalesced.
+func NewCachingTransport(base http.RoundTripper, ttl time.Duration) *CachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &CachingTransport{base: base, ttl: ttl, now: time.Now, entries: map[string]cachedResponse{}}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CachingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet {
+		response, err := t.base.RoundTrip(request)
+		t.invalidate(request.Method, request.URL.Path)
+		return response, err
+	}
+
+	// Polls for a write to become visible must see every new response.
+	if isFreshRead(request.Context()) {
+		return t.base.RoundTrip(request)
+	}
+
+	key := request.Header.Get("Authorization") + " " + request.URL.String()
+
+	if cached, ok := t.lookup(key); ok {
+		tflog.Trace(request.Context(), "Permit.io API read served from cache", map[string]any{"path": request.URL.Path})
+		return cached.response(request), nil
+	}
+
+	t.mu.Lock()
+	generation := t.generation
+	t.mu.Unlock()
+
+	// The generation is part of the key, so a GET sent after a write never
+	// joins a request that started before it. The shared request runs detached
+	// from the context of the first caller, whose cancellation must not fail
+	// the others; each caller stops waiting on its own context instead.
+	shared := request.Clone(context.WithoutCancel(request.Context()))
+	results := t.group.DoChan(fmt.Sprintf("%d %s", generation, key), func() (any, error) {
+		return t.fetch(shared, key, generation)
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		if result.Shared {
+			tflog.Trace(request.Context(), "Permit.io API read shared with a concurrent request", map[string]any{"path": request.URL.Path})
+		}
+
+		return result.Val.(cachedResponse).response(request), nil
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+}
+
+func (t *CachingTransport) fetch(request *http.Request, key string, generation uint64) (cachedResponse, error) {
+	response, err := t.base.RoundTrip(request)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+
+	entry := cachedResponse{
+		path:    request.URL.Path,
+		expires: t.now().Add(t.ttl),
+		status:  response.StatusCode,
+		header:  response.Header.Clone(),
+		body:    body,
+	}
+
+	if t.ttl > 0 && response.StatusCode >= 200 && response.StatusCode < 300 {
+		t.mu.Lock()
+		if t.generation == generation {
+			t.entries[key] = entry
+		}
+		t.mu.Unlock()
+	}
+
+	return entry, nil
+}
+
+func (t *CachingTransport) lookup(key string) (cachedResponse, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return cachedResponse{}, false
+	}
+
+	if !t.now().Before(entry.expires) {
+		delete(t.entries, key)
+		return cachedResponse{}, false
+	}
+
+	return entry, true
+}
+
+func (t *CachingTransport) invalidate(method string, urlPath string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.generation++
+	assignments := RequestFamily(method, urlPath) == RequestFamilyAssignmentWrites
+
+	for key, entry := range t.entries {
+		if CachedPathAffected(entry.path, urlPath) || assignments && isAssignmentRead(entry.path) {
+			delete(t.entries, key)
+		}
+	}
+}
+
+// CachedPathAffected reports whether a write to writePath may change the
+// response of a GET on cachedPath: the same object, an object beneath it, or a
+// collection or parent object above it.
+func CachedPathAffected(cachedPath string, writePath string) bool {
+	cachedPath = strings.TrimSuffix(cachedPath, "/")
+	writePath = strings.TrimSuffix(strings.TrimSuffix(writePath, "/"), "/bulk")
+
+	return isPathWithin(cachedPath, writePath) || isPathWithin(writePath, cachedPath)
+}
+
+func isPathWithin(p string, parent string) bool {
+	return p == parent || strings.HasPrefix(p, parent+"/")
+}
+
+// isAssignmentRead reports whether a GET lists or reads role assignments, which
+// change through several endpoints: the assignments themselves, user and group
+// roles, and relationship tuples.
+func isAssignmentRead(urlPath string) bool {
+	return strings.Contains(urlPath, "/role_assignments") ||
+		strings.Contains(urlPath, "/relationship_tuples") ||
+		strings.Contains(urlPath, "/users/") ||
+		strings.Contains(urlPath, "/groups/")
+}
+
+func (c cachedResponse) response(request *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(c.status),
+		StatusCode:    c.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       request,
+	}
+}
@@ -0,0 +1,209 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedPathAffected(t *testing.T) {
+	tests := []struct {
+		cached string
+		write  string
+		want   bool
+	}{
+		{"/v2/schema/p/e/resources/doc/roles/editor", "/v2/schema/p/e/resources/doc/roles/editor", true},
+		{"/v2/schema/p/e/resources/doc/roles", "/v2/schema/p/e/resources/doc/roles/editor", true},
+		{"/v2/schema/p/e/resources/doc/roles/editor", "/v2/schema/p/e/resources/doc", true},
+		{"/v2/schema/p/e/resources/doc/roles/editor", "/v2/schema/p/e/resources/doc/roles/editor/implicit_grants", true},
+		{"/v2/facts/p/e/role_assignments", "/v2/facts/p/e/role_assignments/bulk", true},
+		{"/v2/schema/p/e/resources/doc/roles/editor", "/v2/schema/p/e/resources/doc/roles/viewer", false},
+		{"/v2/schema/p/e/resources/document", "/v2/schema/p/e/resources/doc", false},
+	}
+
+	for _, tt := range tests {
+		if got := CachedPathAffected(tt.cached, tt.write); got != tt.want {
+			t.Errorf("CachedPathAffected(%q, %q) = %v, want %v", tt.cached, tt.write, got, tt.want)
+		}
+	}
+}
+
+func TestCachingTransport(t *testing.T) {
+	var requests int64
+	release := make(chan struct{})
+	hold := make(chan struct{})
+	block := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		if r.URL.Query().Get("wait") != "" {
+			<-release
+		}
+		if r.URL.Query().Get("hold") != "" {
+			<-hold
+		}
+		if r.URL.Query().Get("block") != "" {
+			<-block
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = io.WriteString(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	transport := NewCachingTransport(nil, time.Minute)
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	get := func(t *testing.T, url string) string {
+		t.Helper()
+		resp, err := client.Get(server.URL + url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	count := func() int64 {
+		return atomic.SwapInt64(&requests, 0)
+	}
+
+	t.Run("repeated reads are cached", func(t *testing.T) {
+		get(t, "/v2/schema/p/e/resources/doc")
+		if got := get(t, "/v2/schema/p/e/resources/doc"); got != "/v2/schema/p/e/resources/doc" {
+			t.Errorf("cached body = %q", got)
+		}
+		if got := count(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("writes invalidate", func(t *testing.T) {
+		resp, err := client.Post(server.URL+"/v2/schema/p/e/resources/doc/roles", "application/json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		get(t, "/v2/schema/p/e/resources/doc")
+		if got := count(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		get(t, "/v2/schema/p/e/roles")
+		now = now.Add(2 * time.Minute)
+		get(t, "/v2/schema/p/e/roles")
+		if got := count(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		get(t, "/missing")
+		get(t, "/missing")
+		if got := count(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("fresh reads bypass the cache", func(t *testing.T) {
+		get(t, "/v2/schema/p/e/tenants")
+		request, _ := http.NewRequestWithContext(FreshRead(context.Background()), http.MethodGet, server.URL+"/v2/schema/p/e/tenants", nil)
+		resp, err := client.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := count(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("concurrent reads are coalesced", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				get(t, "/v2/schema/p/e/resources?wait=1")
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		if got := count(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("reads after a write do not join earlier reads", func(t *testing.T) {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			get(t, "/v2/schema/p/e/tenants?hold=1")
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		resp, err := client.Post(server.URL+"/v2/schema/p/e/tenants", "application/json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		go func() {
+			defer wg.Done()
+			get(t, "/v2/schema/p/e/tenants?hold=1")
+		}()
+		time.Sleep(50 * time.Millisecond)
+		close(hold)
+		wg.Wait()
+		if got := count(); got != 3 {
+			t.Errorf("requests = %d, want 3", got)
+		}
+	})
+
+	t.Run("cancelling one caller does not fail the others", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan error)
+		go func() {
+			request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v2/schema/p/e/users?block=1", nil)
+			resp, err := client.Do(request)
+			if err == nil {
+				resp.Body.Close()
+			}
+			cancelled <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		var body string
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			body = get(t, "/v2/schema/p/e/users?block=1")
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		cancel()
+		if err := <-cancelled; err == nil {
+			t.Error("expected the cancelled read to fail")
+		}
+		close(block)
+		<-done
+		if body != "/v2/schema/p/e/users" {
+			t.Errorf("body = %q", body)
+		}
+		if got := count(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+}
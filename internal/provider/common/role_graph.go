@@ -0,0 +1,304 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+// RoleNode is a role in an environment's role graph: a top-level role when
+// Resource is empty, a resource role otherwise.
+type RoleNode struct {
+	Resource    string
+	Key         string
+	Permissions []string
+	Extends     []string
+	// GrantedTo holds the derivation rules of the role: users who have Role on
+	// a related OnResource instance also get this role.
+	GrantedTo []models.DerivedRoleRuleRead
+}
+
+// RoleNodeId identifies a role in a RoleGraph, as "role" for a top-level role
+// or "resource#role" for a resource role.
+func RoleNodeId(resource string, key string) string {
+	if resource == "" {
+		return key
+	}
+	return resource + "#" + key
+}
+
+func (n *RoleNode) Id() string {
+	return RoleNodeId(n.Resource, n.Key)
+}
+
+// RoleRelation is a relation from ObjectResource to SubjectResource.
+type RoleRelation struct {
+	ObjectResource  string
+	SubjectResource string
+	Key             string
+}
+
+// RoleGraph holds every role and relation of an environment, so that extends
+// chains and role derivations can be followed without further API calls.
+type RoleGraph struct {
+	Roles     map[string]*RoleNode
+	Relations []RoleRelation
+}
+
+// DerivedRole is a role granted through a derivation rule.
+type DerivedRole struct {
+	Target   string
+	Relation string
+}
+
+// Derivations indexes the derivation rules by the role they are granted on:
+// users holding the source role also get each of the derived roles on
+// instances linked by the relation.
+func (g RoleGraph) Derivations() map[string][]DerivedRole {
+	index := map[string][]DerivedRole{}
+
+	for id, node := range g.Roles {
+		for _, rule := range node.GrantedTo {
+			source := RoleNodeId(rule.OnResource, rule.Role)
+			index[source] = append(index[source], DerivedRole{Target: id, Relation: rule.LinkedByRelation})
+		}
+	}
+
+	for source := range index {
+		sort.Slice(index[source], func(i, j int) bool { return index[source][i].Target < index[source][j].Target })
+	}
+
+	return index
+}
+
+// FindExtendsCycle returns the roles of an extends chain that leads from start
+// back to itself, or nil when there is none.
+func (g RoleGraph) FindExtendsCycle(start string) []string {
+	return findCycle(start, func(id string) []string {
+		node, ok := g.Roles[id]
+		if !ok {
+			return nil
+		}
+
+		next := make([]string, 0, len(node.Extends))
+		for _, key := range node.Extends {
+			next = append(next, RoleNodeId(node.Resource, key))
+		}
+		sort.Strings(next)
+		return next
+	})
+}
+
+// FindDerivationCycle returns the roles of a chain of derivations that leads
+// from start back to itself, or nil when there is none.
+func (g RoleGraph) FindDerivationCycle(start string) []string {
+	derivations := g.Derivations()
+
+	return findCycle(start, func(id string) []string {
+		next := make([]string, 0, len(derivations[id]))
+		for _, derived := range derivations[id] {
+			next = append(next, derived.Target)
+		}
+		return next
+	})
+}
+
+func findCycle(start string, edges func(id string) []string) []string {
+	visited := map[string]bool{}
+
+	var walk func(id string, path []string) []string
+	walk = func(id string, path []string) []string {
+		for _, next := range edges(id) {
+			if next == start {
+				return append(path, next)
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+
+			if cycle := walk(next, append(path, next)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return walk(start, []string{start})
+}
+
+// WithRole returns a copy of the graph in which node replaces the role with
+// the same ID, leaving the graph itself unchanged.
+func (g RoleGraph) WithRole(node *RoleNode) RoleGraph {
+	roles := make(map[string]*RoleNode, len(g.Roles)+1)
+	for id, existing := range g.Roles {
+		roles[id] = existing
+	}
+	roles[node.Id()] = node
+
+	return RoleGraph{Roles: roles, Relations: g.Relations}
+}
+
+// RelationLinks reports whether a relation with the given key exists at all,
+// and whether one of them is defined on objectResource with subjectResource as
+// its subject.
+func (g RoleGraph) RelationLinks(key string, objectResource string, subjectResource string) (bool, bool) {
+	exists := false
+
+	for _, relation := range g.Relations {
+		if relation.Key != key {
+			continue
+		}
+		exists = true
+
+		if relation.ObjectResource == objectResource && relation.SubjectResource == subjectResource {
+			return true, true
+		}
+	}
+
+	return exists, false
+}
+
+// roleGraphTTL is how long a shared role graph is used before it is loaded
+// again.
+const roleGraphTTL = 30 * time.Second
+
+// sharedRoleGraph loads the role graph once for every role and derivation
+// planned together, instead of once for each of them.
+type sharedRoleGraph struct {
+	load func(ctx context.Context) (RoleGraph, error)
+	now  func() time.Time
+
+	mu      sync.Mutex
+	loading *roleGraphLoad
+}
+
+type roleGraphLoad struct {
+	ready   chan struct{}
+	expires time.Time
+	graph   RoleGraph
+	err     error
+}
+
+var roleGraphs sync.Map
+
+// SharedRoleGraph returns the role graph of the environment of client, shared
+// by every resource and data source using it until a write invalidates it or
+// it expires. The graph must not be modified; use WithRole to overlay a
+// planned role.
+func SharedRoleGraph(ctx context.Context, client *permit.Client) (RoleGraph, error) {
+	return sharedRoleGraphFor(client).get(ctx)
+}
+
+// InvalidateRoleGraph drops the shared role graph of client, after a role,
+// role derivation or relation was written.
+func InvalidateRoleGraph(client *permit.Client) {
+	sharedRoleGraphFor(client).invalidate()
+}
+
+func sharedRoleGraphFor(client *permit.Client) *sharedRoleGraph {
+	shared, _ := roleGraphs.LoadOrStore(client, newSharedRoleGraph(func(ctx context.Context) (RoleGraph, error) {
+		return LoadRoleGraph(ctx, client)
+	}))
+	return shared.(*sharedRoleGraph)
+}
+
+func newSharedRoleGraph(load func(ctx context.Context) (RoleGraph, error)) *sharedRoleGraph {
+	return &sharedRoleGraph{load: load, now: time.Now}
+}
+
+func (s *sharedRoleGraph) get(ctx context.Context) (RoleGraph, error) {
+	s.mu.Lock()
+	loading := s.loading
+	if loading == nil || !loading.expires.IsZero() && !s.now().Before(loading.expires) {
+		loading = &roleGraphLoad{ready: make(chan struct{})}
+		s.loading = loading
+		// The graph is loaded detached from the context of the first caller,
+		// whose cancellation must not fail the others.
+		go s.fill(context.WithoutCancel(ctx), loading)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-loading.ready:
+		return loading.graph, loading.err
+	case <-ctx.Done():
+		return RoleGraph{}, ctx.Err()
+	}
+}
+
+func (s *sharedRoleGraph) fill(ctx context.Context, loading *roleGraphLoad) {
+	defer close(loading.ready)
+
+	graph, err := s.load(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loading.graph, loading.err = graph, err
+	if err != nil {
+		// Let the next caller try again.
+		if s.loading == loading {
+			s.loading = nil
+		}
+		return
+	}
+	loading.expires = s.now().Add(roleGraphTTL)
+}
+
+func (s *sharedRoleGraph) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loading = nil
+}
+
+// LoadRoleGraph reads every top-level role, resource role and relation in the
+// environment. Like ListResources, it leaves out built-in resources.
+func LoadRoleGraph(ctx context.Context, client *permit.Client) (RoleGraph, error) {
+	graph := RoleGraph{Roles: map[string]*RoleNode{}}
+
+	roles, err := ListAllPages(func(page int, perPage int) ([]models.RoleRead, error) {
+		return client.Api.Roles.List(ctx, page, perPage)
+	})
+
+	if err != nil {
+		return RoleGraph{}, fmt.Errorf("failed listing roles: %w", err)
+	}
+
+	for _, role := range roles {
+		node := &RoleNode{Key: role.Key, Permissions: role.Permissions, Extends: role.Extends}
+		graph.Roles[node.Id()] = node
+	}
+
+	resources, err := ListResources(ctx, client, ResourceListOptions{Roles: true, Relations: true})
+
+	if err != nil {
+		return RoleGraph{}, err
+	}
+
+	for _, resource := range resources {
+		for _, role := range resource.Roles {
+			node := &RoleNode{Resource: resource.Key, Key: role.Key, Permissions: role.Permissions, Extends: role.Extends}
+			if role.GrantedTo != nil {
+				node.GrantedTo = role.GrantedTo.UsersWithRole
+			}
+			graph.Roles[node.Id()] = node
+		}
+
+		for _, relation := range resource.Relations {
+			graph.Relations = append(graph.Relations, RoleRelation{
+				ObjectResource:  relation.ObjectResource,
+				SubjectResource: relation.SubjectResource,
+				Key:             relation.Key,
+			})
+		}
+	}
+
+	return graph, nil
+}
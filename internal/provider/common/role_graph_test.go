@@ -0,0 +1,156 @@
+package common
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+func testRoleGraph() RoleGraph {
+	graph := RoleGraph{
+		Roles: map[string]*RoleNode{},
+		Relations: []RoleRelation{
+			{ObjectResource: "document", SubjectResource: "folder", Key: "parent"},
+		},
+	}
+
+	for _, node := range []*RoleNode{
+		{Key: "admin", Extends: []string{"editor"}},
+		{Key: "editor", Extends: []string{"viewer"}},
+		{Key: "viewer"},
+		{Resource: "folder", Key: "editor"},
+		{Resource: "document", Key: "editor", GrantedTo: []models.DerivedRoleRuleRead{
+			{Role: "editor", OnResource: "folder", LinkedByRelation: "parent"},
+		}},
+	} {
+		graph.Roles[node.Id()] = node
+	}
+
+	return graph
+}
+
+func TestFindExtendsCycle(t *testing.T) {
+	graph := testRoleGraph()
+
+	if cycle := graph.FindExtendsCycle("admin"); cycle != nil {
+		t.Errorf("FindExtendsCycle(admin) = %q, want nil", cycle)
+	}
+
+	graph.Roles["viewer"].Extends = []string{"admin"}
+
+	want := []string{"admin", "editor", "viewer", "admin"}
+	if cycle := graph.FindExtendsCycle("admin"); !reflect.DeepEqual(cycle, want) {
+		t.Errorf("FindExtendsCycle(admin) = %q, want %q", cycle, want)
+	}
+}
+
+func TestFindDerivationCycle(t *testing.T) {
+	graph := testRoleGraph()
+
+	if cycle := graph.FindDerivationCycle("document#editor"); cycle != nil {
+		t.Errorf("FindDerivationCycle() = %q, want nil", cycle)
+	}
+
+	graph.Roles["folder#editor"].GrantedTo = []models.DerivedRoleRuleRead{
+		{Role: "editor", OnResource: "document", LinkedByRelation: "parent"},
+	}
+
+	want := []string{"document#editor", "folder#editor", "document#editor"}
+	if cycle := graph.FindDerivationCycle("document#editor"); !reflect.DeepEqual(cycle, want) {
+		t.Errorf("FindDerivationCycle() = %q, want %q", cycle, want)
+	}
+}
+
+func TestRelationLinks(t *testing.T) {
+	graph := testRoleGraph()
+
+	tests := []struct {
+		key        string
+		object     string
+		subject    string
+		wantExists bool
+		wantLinks  bool
+	}{
+		{"parent", "document", "folder", true, true},
+		{"parent", "folder", "document", true, false},
+		{"parent", "document", "report", true, false},
+		{"owner", "document", "folder", false, false},
+	}
+
+	for _, tt := range tests {
+		exists, links := graph.RelationLinks(tt.key, tt.object, tt.subject)
+		if exists != tt.wantExists || links != tt.wantLinks {
+			t.Errorf("RelationLinks(%q, %q, %q) = %v, %v, want %v, %v", tt.key, tt.object, tt.subject, exists, links, tt.wantExists, tt.wantLinks)
+		}
+	}
+}
+
+func TestWithRole(t *testing.T) {
+	graph := testRoleGraph()
+	overlaid := graph.WithRole(&RoleNode{Resource: "document", Key: "owner"})
+
+	if _, ok := overlaid.Roles["document#owner"]; !ok {
+		t.Error("WithRole() did not add the role")
+	}
+	if _, ok := graph.Roles["document#owner"]; ok {
+		t.Error("WithRole() changed the original graph")
+	}
+}
+
+func TestSharedRoleGraph(t *testing.T) {
+	var loads int64
+	release := make(chan struct{})
+
+	shared := newSharedRoleGraph(func(context.Context) (RoleGraph, error) {
+		atomic.AddInt64(&loads, 1)
+		<-release
+		return testRoleGraph(), nil
+	})
+	now := time.Now()
+	shared.now = func() time.Time { return now }
+
+	// The first caller gives up, which must not fail the others.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error)
+	go func() {
+		_, err := shared.get(cancelled)
+		cancelledErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-cancelledErr; err == nil {
+		t.Error("get() with a cancelled context succeeded")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if graph, err := shared.get(context.Background()); err != nil || len(graph.Roles) == 0 {
+				t.Errorf("get() = %v, %v", graph, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&loads); got != 1 {
+		t.Errorf("loads = %d, want 1", got)
+	}
+
+	shared.invalidate()
+	_, _ = shared.get(context.Background())
+	now = now.Add(2 * roleGraphTTL)
+	_, _ = shared.get(context.Background())
+
+	if got := atomic.LoadInt64(&loads); got != 3 {
+		t.Errorf("loads after invalidating and expiring = %d, want 3", got)
+	}
+}
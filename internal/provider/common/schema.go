@@ -1,9 +1,13 @@
 package common
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
 
 func CreateBaseResourceSchema() map[string]schema.Attribute {
@@ -21,6 +25,7 @@ func CreateBaseResourceSchema() map[string]schema.Attribute {
 			PlanModifiers: []planmodifier.String{
 				stringplanmodifier.RequiresReplace(),
 			},
+			Validators: KeyValidators(),
 		},
 		"name": schema.StringAttribute{
 			MarkdownDescription: "The name. This is a human-readable name for the object. ",
@@ -66,3 +71,50 @@ func CreateBaseResourceSchema() map[string]schema.Attribute {
 		},
 	}
 }
+
+// AdoptExistingAttribute is the per-resource override of the provider-level
+// adopt_existing setting.
+func AdoptExistingAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether to take over an object with the same key that already exists in Permit when creating it, instead of failing with a conflict. " +
+			"The existing object is updated to match the configuration when it differs. Overrides the provider-level `adopt_existing` setting.",
+		Optional: true,
+	}
+}
+
+// WaitForPDPAttribute is the per-resource opt-in to the provider-level
+// wait_for_pdp block.
+func WaitForPDPAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether applying a change waits until the PDP configured in the provider `wait_for_pdp` block reflects it, " +
+			"so that checks made right after the apply see it. Defaults to `false`.",
+		Optional: true,
+	}
+}
+
+// DeletionProtectionAttribute guards objects whose deletion cascades to
+// everything beneath them in Permit.
+func DeletionProtectionAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether Terraform is prevented from deleting this object. Deleting it in Permit also deletes everything beneath it, " +
+			"so it must first be set to `false` and applied before the object can be destroyed or replaced. Defaults to `false`.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+// AttributesModeAttribute selects how much of an object's attributes Terraform
+// owns. See AttributesForState and MergeAttributes.
+func AttributesModeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "How Terraform manages `attributes`. With `authoritative`, the attributes in Permit are replaced by the configured ones and any other key shows up as a change. " +
+			"With `merge`, only the configured keys are compared and written, and keys set outside of Terraform, for example by your application, are kept. Defaults to `authoritative`.",
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(AttributesModeAuthoritative),
+		Validators: []validator.String{
+			stringvalidator.OneOf(AttributesModeAuthoritative, AttributesModeMerge),
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// DecodeRawState decodes state that was written with an earlier version of a
+// resource schema, the same way the framework does before handing it to a
+// state upgrader with a PriorSchema. It lets tests feed recorded state through
+// the upgraders.
+func DecodeRawState(ctx context.Context, priorSchema schema.Schema, rawJSON string) (*tfsdk.State, error) {
+	rawState := tfprotov6.RawState{JSON: []byte(rawJSON)}
+
+	value, err := rawState.Unmarshal(priorSchema.Type().TerraformType(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfsdk.State{Schema: priorSchema, Raw: value}, nil
+}
+
+// NullState returns an empty state for the given schema, matching what the
+// framework passes to a state upgrader as the response state.
+func NullState(ctx context.Context, s schema.Schema) tfsdk.State {
+	return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+}
+
+// JSONStringAttributesUpgrader upgrades state written while the named
+// attributes were JSON-encoded strings to a schema where they are dynamic.
+// The strings are kept as they are, so configurations that still use
+// jsonencode() plan no changes.
+func JSONStringAttributesUpgrader(names ...string) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(_ context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+			if request.RawState == nil {
+				response.Diagnostics.AddError("Unable to upgrade state", "no prior state was provided")
+				return
+			}
+
+			var state map[string]json.RawMessage
+			if err := json.Unmarshal(request.RawState.JSON, &state); err != nil {
+				response.Diagnostics.AddError("Unable to upgrade state", err.Error())
+				return
+			}
+
+			for _, name := range names {
+				var value *string
+				if err := json.Unmarshal(state[name], &value); err != nil || value == nil {
+					state[name] = json.RawMessage("null")
+					continue
+				}
+
+				// Dynamic values are stored together with their type.
+				wrapped, err := json.Marshal(map[string]any{"value": *value, "type": "string"})
+				if err != nil {
+					response.Diagnostics.AddError("Unable to upgrade state", err.Error())
+					return
+				}
+				state[name] = wrapped
+			}
+
+			upgraded, err := json.Marshal(state)
+			if err != nil {
+				response.Diagnostics.AddError("Unable to upgrade state", err.Error())
+				return
+			}
+
+			response.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
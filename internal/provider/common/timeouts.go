@@ -0,0 +1,36 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultOperationTimeout bounds a whole create, read, update or delete,
+// including its retries and polling, when the timeouts block does not set one.
+// Each request within the operation is still bounded by the provider timeout.
+const DefaultOperationTimeout = 20 * time.Minute
+
+// TimeoutsBlock returns the timeouts block accepted by every resource.
+func TimeoutsBlock() schema.Block {
+	return timeouts.BlockAll(context.Background())
+}
+
+// NullTimeouts returns an unset timeouts block, for state upgraded from a
+// schema version that had none.
+func NullTimeouts() timeouts.Value {
+	return timeouts.Value{Object: types.ObjectNull(TimeoutsBlock().Type().(timeouts.Type).AttrTypes)}
+}
+
+// OperationContext bounds ctx by the timeout returned by get, which is the
+// Create, Read, Update or Delete method of the resource's timeouts.
+func OperationContext(ctx context.Context, get func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	timeout, timeoutDiags := get(ctx, DefaultOperationTimeout)
+	diags.Append(timeoutDiags...)
+
+	return context.WithTimeout(ctx, timeout)
+}
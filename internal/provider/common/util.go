@@ -2,6 +2,9 @@ package common
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
+
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 )
 
@@ -27,3 +30,28 @@ func ConvertElementsToSlice[T any](ctx context.Context, elements []attr.Value) (
 
 	return slice, nil
 }
+
+// JSONObjectsEqual reports whether two JSON-encoded objects hold the same data,
+// ignoring key order and formatting. An empty string is treated as an empty
+// object, matching how attributes left unset are sent to the API.
+func JSONObjectsEqual(a, b string) bool {
+	var aValue, bValue map[string]any
+
+	if a != "" {
+		if err := json.Unmarshal([]byte(a), &aValue); err != nil {
+			return false
+		}
+	}
+
+	if b != "" {
+		if err := json.Unmarshal([]byte(b), &bValue); err != nil {
+			return false
+		}
+	}
+
+	if len(aValue) == 0 && len(bValue) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(aValue, bValue)
+}
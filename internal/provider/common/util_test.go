@@ -0,0 +1,28 @@
+package common
+
+import "testing"
+
+func TestJSONObjectsEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{"both empty", "", "", true},
+		{"empty and empty object", "", "{}", true},
+		{"key order", `{"plan":"pro","seats":5}`, `{"seats":5,"plan":"pro"}`, true},
+		{"nested", `{"tags":["a","b"],"meta":{"x":true}}`, `{"meta":{"x":true},"tags":["a","b"]}`, true},
+		{"different value", `{"plan":"pro"}`, `{"plan":"free"}`, false},
+		{"extra key", `{"plan":"pro"}`, `{"plan":"pro","seats":5}`, false},
+		{"invalid", `{"plan":`, `{"plan":"pro"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JSONObjectsEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("JSONObjectsEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
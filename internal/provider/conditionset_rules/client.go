@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/permit"
 )
@@ -17,6 +18,9 @@ type ConditionSetRuleModel struct {
 	UserSet        types.String `tfsdk:"user_set"`
 	Permission     types.String `tfsdk:"permission"`
 	ResourceSet    types.String `tfsdk:"resource_set"`
+
+	WaitForPDP types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 type ConditionSetRuleClient struct {
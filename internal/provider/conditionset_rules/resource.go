@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/permitio/permit-golang/pkg/permit"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"strings"
+	"time"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -89,6 +91,7 @@ func (c *ConditionSetRuleResource) Schema(_ context.Context, _ resource.SchemaRe
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"permission": schema.StringAttribute{
 				Required:            true,
@@ -96,6 +99,9 @@ func (c *ConditionSetRuleResource) Schema(_ context.Context, _ resource.SchemaRe
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					common.PermissionValidator(),
+				},
 			},
 			"resource_set": schema.StringAttribute{
 				Required:            true,
@@ -103,7 +109,12 @@ func (c *ConditionSetRuleResource) Schema(_ context.Context, _ resource.SchemaRe
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
+			"wait_for_pdp": common.WaitForPDPAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
 		},
 	}
 }
@@ -120,6 +131,14 @@ func (c *ConditionSetRuleResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "condition set rule", plan.WaitForPDP, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if err := c.client.Create(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create condition set rule",
@@ -135,6 +154,14 @@ func (c *ConditionSetRuleResource) Create(ctx context.Context, req resource.Crea
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(common.MarkCreated(ctx, resp.Private, time.Now())...)
+
+	common.AwaitWrite(ctx, "condition set rule", func(ctx context.Context) (bool, error) {
+		_, err := c.client.Read(ctx, plan)
+		return err == nil, err
+	}, &resp.Diagnostics)
+	policyWait.Await(ctx, &resp.Diagnostics)
 }
 
 func (c *ConditionSetRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -145,7 +172,15 @@ func (c *ConditionSetRuleResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	state, err := c.client.Read(ctx, data)
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+
+	var state ConditionSetRuleModel
+	err := common.RetryRecentNotFound(ctx, req.Private, func(ctx context.Context) error {
+		var err error
+		state, err = c.client.Read(ctx, data)
+		return err
+	})
 
 	if err != nil {
 		// If the rule no longer exists in Permit, drop it from state so it is recreated.
@@ -168,10 +203,20 @@ func (c *ConditionSetRuleResource) Read(ctx context.Context, req resource.ReadRe
 	}
 }
 
-// Update updates the resource and sets the updated Terraform state on success.
-func (c *ConditionSetRuleResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
-	// rules cannot be updated, only replaced - this should never be called
-	panic("updating ConditionSetRules is not implemented")
+// Update only stores changed wait_for_pdp and timeouts, as rules cannot be
+// updated, only replaced.
+func (c *ConditionSetRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state ConditionSetRuleModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.WaitForPDP = plan.WaitForPDP
+	state.Timeouts = plan.Timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -185,6 +230,14 @@ func (c *ConditionSetRuleResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "condition set rule", state.WaitForPDP, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := c.client.Delete(ctx, &state)
 
 	if err != nil {
@@ -194,6 +247,8 @@ func (c *ConditionSetRuleResource) Delete(ctx context.Context, req resource.Dele
 		)
 		return
 	}
+
+	policyWait.Await(ctx, &resp.Diagnostics)
 }
 
 // ImportState implements resource.ResourceWithImportState.
@@ -1,12 +1,18 @@
 package conditionsets
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
 )
 
 type ConditionSetModel struct {
@@ -20,6 +26,29 @@ type ConditionSetModel struct {
 	Conditions     types.String `tfsdk:"conditions"`
 	Resource       types.String `tfsdk:"resource"`
 	ParentId       types.String `tfsdk:"parent_id"`
+	Parent         types.String `tfsdk:"parent"`
+}
+
+// parentId returns the parent to send to the API: the key in parent, which
+// takes precedence, or the ID in parent_id. It is nil when neither is set.
+func (m *ConditionSetModel) parentId() (*models.ParentId, error) {
+	parent := m.Parent.ValueString()
+
+	if m.Parent.IsNull() || m.Parent.IsUnknown() || parent == "" {
+		if m.ParentId.IsNull() || m.ParentId.IsUnknown() || m.ParentId.ValueString() == "" {
+			return nil, nil
+		}
+		parent = m.ParentId.ValueString()
+	}
+
+	var parentId models.ParentId
+	err := json.Unmarshal([]byte(fmt.Sprintf("\"%s\"", parent)), &parentId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &parentId, nil
 }
 
 type ConditionSetClient struct {
@@ -82,6 +111,19 @@ func (c *ConditionSetClient) Read(ctx context.Context, data ConditionSetModel) (
 		parentId = types.StringPointerValue(nil)
 	}
 
+	// parent is only tracked when it was configured, so that reading a
+	// condition set does not look up its parent otherwise.
+	parent := types.StringNull()
+	if !data.Parent.IsNull() && !parentId.IsNull() {
+		parentRead, err := c.client.Api.ConditionSets.Get(ctx, parentId.ValueString())
+
+		if err != nil {
+			return ConditionSetModel{}, fmt.Errorf("failed reading parent condition set %s: %w", parentId.ValueString(), err)
+		}
+
+		parent = types.StringValue(parentRead.Key)
+	}
+
 	state := ConditionSetModel{
 		Id:             types.StringValue(conditionSet.Id),
 		OrganizationId: types.StringValue(conditionSet.OrganizationId),
@@ -92,6 +134,7 @@ func (c *ConditionSetClient) Read(ctx context.Context, data ConditionSetModel) (
 		Description:    description,
 		Resource:       resource,
 		ParentId:       parentId,
+		Parent:         parent,
 		Conditions:     types.StringValue(string(conditionsMarshalled)),
 	}
 
@@ -130,19 +173,14 @@ func (c *ConditionSetClient) Create(ctx context.Context, conditionSetType models
 		conditionSetCreate.ResourceId = &resourceId
 	}
 
-	// Only set parent_id if it's not null and not empty
-	if !conditionSetPlan.ParentId.IsNull() && conditionSetPlan.ParentId.ValueString() != "" {
-		var parentId models.ParentId
-		parentIdStr := conditionSetPlan.ParentId.ValueString()
-		err = json.Unmarshal([]byte(fmt.Sprintf("\"%s\"", parentIdStr)), &parentId)
-
-		if err != nil {
-			return err
-		}
+	parentId, err := conditionSetPlan.parentId()
 
-		conditionSetCreate.ParentId = &parentId
+	if err != nil {
+		return err
 	}
 
+	conditionSetCreate.ParentId = parentId
+
 	conditionSetRead, err := c.client.Api.ConditionSets.Create(ctx, conditionSetCreate)
 
 	if err != nil {
@@ -178,7 +216,9 @@ func (c *ConditionSetClient) Create(ctx context.Context, conditionSetType models
 	return nil
 }
 
-func (c *ConditionSetClient) Update(ctx context.Context, conditionSetPlan *ConditionSetModel) error {
+// Update writes the plan to the condition set. prior is the state before the
+// update; when it had a parent and the plan has none, the set is un-nested.
+func (c *ConditionSetClient) Update(ctx context.Context, conditionSetPlan *ConditionSetModel, prior ConditionSetModel) error {
 	var conditions map[string]any
 	err := json.Unmarshal([]byte(conditionSetPlan.Conditions.ValueString()), &conditions)
 
@@ -192,17 +232,18 @@ func (c *ConditionSetClient) Update(ctx context.Context, conditionSetPlan *Condi
 		Conditions:  conditions,
 	}
 
-	// Only set parent_id if it's not null and not empty
-	if !conditionSetPlan.ParentId.IsNull() && conditionSetPlan.ParentId.ValueString() != "" {
-		var parentId models.ParentId
-		parentIdStr := conditionSetPlan.ParentId.ValueString()
-		err = json.Unmarshal([]byte(fmt.Sprintf("\"%s\"", parentIdStr)), &parentId)
+	parentId, err := conditionSetPlan.parentId()
 
-		if err != nil {
+	if err != nil {
+		return err
+	}
+
+	csUpdate.ParentId = parentId
+
+	if parentId == nil && prior.ParentId.ValueString() != "" {
+		if err := c.clearParent(ctx, prior); err != nil {
 			return err
 		}
-
-		csUpdate.ParentId = &parentId
 	}
 
 	conditionSetRead, err := c.client.Api.ConditionSets.Update(ctx, conditionSetPlan.Key.ValueString(), csUpdate)
@@ -248,6 +289,38 @@ func (c *ConditionSetClient) Update(ctx context.Context, conditionSetPlan *Condi
 	return nil
 }
 
+// clearParent moves the condition set out from under its parent. The SDK leaves
+// a nil parent_id out of updates, so the request is sent directly.
+func (c *ConditionSetClient) clearParent(ctx context.Context, prior ConditionSetModel) error {
+	url := fmt.Sprintf("%s/v2/schema/%s/%s/condition_sets/%s",
+		strings.TrimSuffix(config.GetGlobalApiUrl(), "/"), prior.ProjectId.ValueString(), prior.EnvironmentId.ValueString(), prior.Key.ValueString())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewBufferString(`{"parent_id":null}`))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.GetGlobalApiKey()))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := config.GetHTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed removing the parent of condition set %s: status %d: %s", prior.Key.ValueString(), resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
 func (c *ConditionSetClient) Delete(ctx context.Context, key string) error {
 	return c.client.Api.ConditionSets.Delete(ctx, key)
 }
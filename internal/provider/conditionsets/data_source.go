@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -61,7 +62,8 @@ func (d *ConditionSetDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 				Computed: true,
 			},
 			"key": schema.StringAttribute{
-				Required: true,
+				Required:   true,
+				Validators: common.KeyFormatValidators(),
 			},
 			"name": schema.StringAttribute{
 				Required: true,
@@ -74,7 +76,8 @@ func (d *ConditionSetDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 				Required: true,
 			},
 			"resource": schema.StringAttribute{
-				Optional: true,
+				Optional:   true,
+				Validators: common.KeyFormatValidators(),
 			},
 			"conditions": schema.StringAttribute{
 				Required: true,
@@ -3,20 +3,37 @@ package conditionsets
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &conditionSetResource{}
-	_ resource.ResourceWithConfigure = &conditionSetResource{}
+	_ resource.Resource                = &conditionSetResource{}
+	_ resource.ResourceWithConfigure   = &conditionSetResource{}
+	_ resource.ResourceWithImportState = &conditionSetResource{}
+	_ resource.ResourceWithModifyPlan  = &conditionSetResource{}
 )
 
+// conditionSetResourceModel adds the attributes only the resources have to
+// ConditionSetModel, which is shared with the data source.
+type conditionSetResourceModel struct {
+	ConditionSetModel
+
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
 func NewResourceSetResource() resource.Resource {
 	return &ResourceSetResource{conditionSetResource{conditionSetType: models.RESOURCESET}}
 }
@@ -72,12 +89,16 @@ func (c *conditionSetResource) Metadata(_ context.Context, _ resource.MetadataRe
 func (c *ResourceSetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	attributes := c.baseAttributes()
 	attributes["resource"] = schema.StringAttribute{
-		Required: true,
+		Required:   true,
+		Validators: common.KeyFormatValidators(),
 	}
 
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "See the [our documentation](https://api.permit.io/v2/redoc#tag/Condition-Sets/operation/create_condition_set) for more information on condition sets.",
 		Attributes:          attributes,
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
@@ -87,6 +108,9 @@ func (c *UserSetResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "See the [our documentation](https://api.permit.io/v2/redoc#tag/Condition-Sets/operation/create_condition_set) for more information on condition sets.",
 		Attributes:          attributes,
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
@@ -127,6 +151,7 @@ func (c *conditionSetResource) baseAttributes() map[string]schema.Attribute {
 		"key": schema.StringAttribute{
 			MarkdownDescription: "A unique id by which Permit will identify the condition set. The key will be used as the generated rego rule name.",
 			Required:            true,
+			Validators:          common.KeyValidators(),
 		},
 		"name": schema.StringAttribute{
 			MarkdownDescription: "A descriptive name for the set, i.e: 'US based employees' or 'Users behind VPN'",
@@ -147,6 +172,7 @@ func (c *conditionSetResource) baseAttributes() map[string]schema.Attribute {
 		"resource": schema.StringAttribute{
 			MarkdownDescription: "The resource id to which the condition set applies. This is only required for resource sets.",
 			Optional:            true,
+			Validators:          common.KeyFormatValidators(),
 		},
 		"parent_id": schema.StringAttribute{
 			MarkdownDescription: "The parent condition set id. Allows creating a nested condition set hierarchy.",
@@ -156,12 +182,100 @@ func (c *conditionSetResource) baseAttributes() map[string]schema.Attribute {
 				stringplanmodifier.UseStateForUnknown(),
 			},
 		},
+		"parent": schema.StringAttribute{
+			MarkdownDescription: "The key of the parent condition set, to nest this set under it. The parent must be of the same type and, for resource sets, on the same resource; this is checked when planning. Removing it moves the set out from under its parent. Conflicts with `parent_id`.",
+			Optional:            true,
+			Validators: append(
+				common.KeyFormatValidators(),
+				stringvalidator.ConflictsWith(path.MatchRoot("parent_id")),
+			),
+		},
+		"deletion_protection": common.DeletionProtectionAttribute(),
+	}
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It recomputes
+// parent_id when parent changes or is removed, and checks that the parent has
+// the same type and resource as the planned set.
+func (c *conditionSetResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan conditionSetResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var state conditionSetResourceModel
+	if !request.State.Raw.IsNull() {
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if plan.Parent.IsNull() {
+		// parent_id would otherwise keep the prior parent, which Update
+		// would then send again.
+		if !state.Parent.IsNull() {
+			var configParentId types.String
+			response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("parent_id"), &configParentId)...)
+
+			if configParentId.IsNull() {
+				response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("parent_id"), types.StringNull())...)
+			}
+		}
+		return
+	}
+
+	if plan.Parent.IsUnknown() || plan.Parent.Equal(state.Parent) || c.client.client == nil {
+		return
+	}
+
+	response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("parent_id"), types.StringUnknown())...)
+
+	if plan.Parent.Equal(plan.Key) {
+		response.Diagnostics.AddAttributeError(
+			path.Root("parent"),
+			"Invalid parent condition set",
+			"A condition set cannot be its own parent.",
+		)
+		return
+	}
+
+	parent, err := c.client.client.Api.ConditionSets.Get(ctx, plan.Parent.ValueString())
+
+	if err != nil {
+		// The parent may be created in the same apply.
+		if !common.IsNotFoundErr(err) {
+			response.Diagnostics.AddAttributeWarning(
+				path.Root("parent"),
+				"Unable to check the parent condition set",
+				fmt.Sprintf("Unable to read condition set %s: %s", plan.Parent.ValueString(), err),
+			)
+		}
+		return
+	}
+
+	resourceKey := ""
+	if !plan.Resource.IsUnknown() {
+		resourceKey = plan.Resource.ValueString()
+	}
+
+	if mismatch := parentMismatch(*parent, c.conditionSetType, resourceKey); mismatch != "" {
+		response.Diagnostics.AddAttributeError(
+			path.Root("parent"),
+			"Invalid parent condition set",
+			fmt.Sprintf("The parent must be a condition set of the same type and resource: %s.", mismatch),
+		)
 	}
 }
 
 func (c *conditionSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var (
-		plan ConditionSetModel
+		plan conditionSetResourceModel
 	)
 
 	diags := req.Plan.Get(ctx, &plan)
@@ -171,7 +285,10 @@ func (c *conditionSetResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	if err := c.client.Create(ctx, c.conditionSetType, &plan); err != nil {
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	if err := c.client.Create(ctx, c.conditionSetType, &plan.ConditionSetModel); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create resource",
 			fmt.Sprintf("Unable to create resource: %s", err),
@@ -186,18 +303,23 @@ func (c *conditionSetResource) Create(ctx context.Context, req resource.CreateRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	c.awaitWrite(ctx, plan.ConditionSetModel, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (c *conditionSetResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
-	var data ConditionSetModel
+	var data conditionSetResourceModel
 
 	response.Diagnostics.Append(request.State.Get(ctx, &data)...)
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	state, err := c.client.Read(ctx, data)
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
+	read, err := c.client.Read(ctx, data.ConditionSetModel)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -206,6 +328,11 @@ func (c *conditionSetResource) Read(ctx context.Context, request resource.ReadRe
 		)
 		return
 	}
+	state := conditionSetResourceModel{
+		ConditionSetModel:  read,
+		DeletionProtection: common.DeletionProtectionFromState(data.DeletionProtection),
+		Timeouts:           data.Timeouts,
+	}
 
 	// Set state
 	diags := response.State.Set(ctx, &state)
@@ -218,7 +345,7 @@ func (c *conditionSetResource) Read(ctx context.Context, request resource.ReadRe
 // Update updates the resource and sets the updated Terraform state on success.
 func (c *conditionSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var (
-		plan ConditionSetModel
+		plan conditionSetResourceModel
 	)
 
 	diags := req.Plan.Get(ctx, &plan)
@@ -227,7 +354,16 @@ func (c *conditionSetResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	if err := c.client.Update(ctx, &plan); err != nil {
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+
+	var state conditionSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := c.client.Update(ctx, &plan.ConditionSetModel, state.ConditionSetModel); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update resource",
 			fmt.Sprintf("Unable to update resource: %s", err),
@@ -239,12 +375,23 @@ func (c *conditionSetResource) Update(ctx context.Context, req resource.UpdateRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	c.awaitWrite(ctx, plan.ConditionSetModel, &resp.Diagnostics)
+}
+
+// awaitWrite waits until the condition set reads back with the written name
+// and description.
+func (c *conditionSetResource) awaitWrite(ctx context.Context, written ConditionSetModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "condition set", func(ctx context.Context) (bool, error) {
+		read, err := c.client.Read(ctx, written)
+		return err == nil && common.PlanValueMatches(written.Name, read.Name) && common.PlanValueMatches(written.Description, read.Description), err
+	}, diags)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (c *conditionSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Retrieve values from state
-	var state ConditionSetModel
+	var state conditionSetResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 
@@ -252,6 +399,13 @@ func (c *conditionSetResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	if !common.CheckDeletionProtection(state.DeletionProtection, "condition set", state.Key.ValueString(), &resp.Diagnostics) {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
 	err := c.client.Delete(ctx, state.Key.ValueString())
 
 	if err != nil {
@@ -262,3 +416,9 @@ func (c *conditionSetResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 }
+
+// ImportState implements resource.ResourceWithImportState. The import ID is the
+// condition set key.
+func (c *conditionSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
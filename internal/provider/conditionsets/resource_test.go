@@ -0,0 +1,124 @@
+package conditionsets
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+)
+
+func TestModifyPlanRemovesParent(t *testing.T) {
+	ctx := context.Background()
+	r := NewUserSetResource().(*UserSetResource)
+
+	var schemaResponse resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResponse)
+
+	nested := conditionSetModel("members", types.StringValue("everyone"), types.StringValue("parent-id"))
+
+	tests := []struct {
+		name   string
+		config conditionSetResourceModel
+		plan   conditionSetResourceModel
+		want   types.String
+	}{
+		{
+			// UseStateForUnknown plans the prior parent_id.
+			name:   "parent removed",
+			config: conditionSetModel("members", types.StringNull(), types.StringNull()),
+			plan:   conditionSetModel("members", types.StringNull(), types.StringValue("parent-id")),
+			want:   types.StringNull(),
+		},
+		{
+			name:   "parent replaced by parent_id",
+			config: conditionSetModel("members", types.StringNull(), types.StringValue("other-id")),
+			plan:   conditionSetModel("members", types.StringNull(), types.StringValue("other-id")),
+			want:   types.StringValue("other-id"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: schemaResponse.Schema, Raw: rawValue(t, schemaResponse.Schema, tt.config)},
+				State:  tfsdk.State{Schema: schemaResponse.Schema, Raw: rawValue(t, schemaResponse.Schema, nested)},
+				Plan:   tfsdk.Plan{Schema: schemaResponse.Schema, Raw: rawValue(t, schemaResponse.Schema, tt.plan)},
+			}
+			response := resource.ModifyPlanResponse{Plan: request.Plan}
+
+			r.ModifyPlan(ctx, request, &response)
+			if response.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan() diagnostics = %v", response.Diagnostics)
+			}
+
+			var parentId types.String
+			response.Plan.GetAttribute(ctx, path.Root("parent_id"), &parentId)
+			if !parentId.Equal(tt.want) {
+				t.Errorf("parent_id = %v, want %v", parentId, tt.want)
+			}
+		})
+	}
+}
+
+func conditionSetModel(key string, parent types.String, parentId types.String) conditionSetResourceModel {
+	var m conditionSetResourceModel
+	m.Key = types.StringValue(key)
+	m.Name = types.StringValue(key)
+	m.Conditions = types.StringValue(`{"allOf":[]}`)
+	m.Parent = parent
+	m.ParentId = parentId
+	m.Timeouts = common.NullTimeouts()
+	return m
+}
+
+func rawValue(t *testing.T, s schema.Schema, m conditionSetResourceModel) tftypes.Value {
+	t.Helper()
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if diags := state.Set(context.Background(), m); diags.HasError() {
+		t.Fatalf("State.Set() diagnostics = %v", diags)
+	}
+	return state.Raw
+}
+
+func TestClearParent(t *testing.T) {
+	var method, requestPath, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, requestPath = r.Method, r.URL.Path
+		read, _ := io.ReadAll(r.Body)
+		body = string(read)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config.SetGlobalConfig(server.URL, "test-key")
+	config.SetHTTPClient(server.Client())
+	defer config.SetGlobalConfig("", "")
+	defer config.SetHTTPClient(nil)
+
+	prior := conditionSetModel("members", types.StringValue("everyone"), types.StringValue("parent-id")).ConditionSetModel
+	prior.ProjectId = types.StringValue("project")
+	prior.EnvironmentId = types.StringValue("env")
+
+	c := ConditionSetClient{}
+	if err := c.clearParent(context.Background(), prior); err != nil {
+		t.Fatalf("clearParent() error = %v", err)
+	}
+
+	if method != http.MethodPatch || requestPath != "/v2/schema/project/env/condition_sets/members" {
+		t.Errorf("request = %s %s, want PATCH /v2/schema/project/env/condition_sets/members", method, requestPath)
+	}
+	if body != `{"parent_id":null}` {
+		t.Errorf("body = %s, want an explicit null parent_id", body)
+	}
+}
@@ -0,0 +1,31 @@
+package conditionsets
+
+import (
+	"fmt"
+
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+// parentMismatch describes why a condition set cannot be the parent of a set of
+// the given type and resource, or returns an empty string when it can. Nested
+// sets must have the same type as their parent, and resource sets the same
+// resource, given by key or id.
+func parentMismatch(parent models.ConditionSetRead, setType models.ConditionSetType, resource string) string {
+	if parentType := parent.GetType(); parentType != setType {
+		return fmt.Sprintf("condition set %s is a %s, it cannot be the parent of a %s", parent.Key, parentType, setType)
+	}
+
+	if setType != models.RESOURCESET || resource == "" {
+		return ""
+	}
+
+	if parent.Resource == nil {
+		return fmt.Sprintf("condition set %s does not apply to a resource, it cannot be the parent of a resource set on %s", parent.Key, resource)
+	}
+
+	if parent.Resource.Key != resource && parent.Resource.Id != resource {
+		return fmt.Sprintf("condition set %s applies to resource %s, it cannot be the parent of a resource set on %s", parent.Key, parent.Resource.Key, resource)
+	}
+
+	return ""
+}
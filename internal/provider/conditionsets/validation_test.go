@@ -0,0 +1,41 @@
+package conditionsets
+
+import (
+	"testing"
+
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+func TestParentMismatch(t *testing.T) {
+	userSet := models.USERSET
+	resourceSet := models.RESOURCESET
+
+	admins := models.ConditionSetRead{Key: "admins", Type: &userSet}
+	documents := models.ConditionSetRead{Key: "documents", Type: &resourceSet, Resource: &models.ResourceRead{Key: "document", Id: "b1c2"}}
+	orphan := models.ConditionSetRead{Key: "orphan", Type: &resourceSet}
+
+	tests := []struct {
+		name     string
+		parent   models.ConditionSetRead
+		setType  models.ConditionSetType
+		resource string
+		want     bool
+	}{
+		{"same user set type", admins, models.USERSET, "", false},
+		{"user set under resource set", documents, models.USERSET, "", true},
+		{"resource set under user set", admins, models.RESOURCESET, "document", true},
+		{"same resource by key", documents, models.RESOURCESET, "document", false},
+		{"same resource by id", documents, models.RESOURCESET, "b1c2", false},
+		{"other resource", documents, models.RESOURCESET, "folder", true},
+		{"unknown resource", documents, models.RESOURCESET, "", false},
+		{"parent without resource", orphan, models.RESOURCESET, "document", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parentMismatch(tt.parent, tt.setType, tt.resource); (got != "") != tt.want {
+				t.Errorf("parentMismatch(%s, %s, %q) = %q, want mismatch %v", tt.parent.Key, tt.setType, tt.resource, got, tt.want)
+			}
+		})
+	}
+}
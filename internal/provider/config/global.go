@@ -1,9 +1,25 @@
 package config
 
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultApiUrl is the Permit.io API used when the provider does not set one.
+const DefaultApiUrl = "https://api.permit.io"
+
 // Global config storage for resources that need direct HTTP access.
 var (
-	globalApiUrl string
-	globalApiKey string
+	globalApiUrl     string
+	globalApiKey     string
+	globalHTTPClient *http.Client
+
+	globalAdoptExisting      bool
+	globalWaitForConsistency bool
+
+	globalPDPUrl     string
+	globalPDPOpaUrl  string
+	globalPDPTimeout time.Duration
 )
 
 // SetGlobalConfig stores the API URL and key globally.
@@ -21,3 +37,63 @@ func GetGlobalApiUrl() string {
 func GetGlobalApiKey() string {
 	return globalApiKey
 }
+
+// SetAdoptExisting stores the provider-level adopt_existing setting.
+func SetAdoptExisting(adopt bool) {
+	globalAdoptExisting = adopt
+}
+
+// GetAdoptExisting returns the provider-level adopt_existing setting.
+func GetAdoptExisting() bool {
+	return globalAdoptExisting
+}
+
+// SetWaitForConsistency stores the provider-level wait_for_consistency setting.
+func SetWaitForConsistency(wait bool) {
+	globalWaitForConsistency = wait
+}
+
+// GetWaitForConsistency returns the provider-level wait_for_consistency setting.
+func GetWaitForConsistency() bool {
+	return globalWaitForConsistency
+}
+
+// SetHTTPClient stores the HTTP client shared with the Permit.io SDK, so that
+// direct HTTP calls count against the same concurrency limits.
+func SetHTTPClient(client *http.Client) {
+	globalHTTPClient = client
+}
+
+// GetHTTPClient returns the shared HTTP client, or http.DefaultClient when the
+// provider has not been configured.
+func GetHTTPClient() *http.Client {
+	if globalHTTPClient == nil {
+		return http.DefaultClient
+	}
+	return globalHTTPClient
+}
+
+// SetWaitForPDP stores the PDP that resources opting into wait_for_pdp poll
+// after a write, and how long they poll it for.
+func SetWaitForPDP(pdpUrl string, timeout time.Duration) {
+	globalPDPUrl = pdpUrl
+	globalPDPTimeout = timeout
+}
+
+// GetWaitForPDP returns the PDP URL and timeout of the provider-level
+// wait_for_pdp block. The URL is empty when the block is not configured.
+func GetWaitForPDP() (string, time.Duration) {
+	return globalPDPUrl, globalPDPTimeout
+}
+
+// SetWaitForPDPOpaUrl stores the OPA of the PDP that policy resources opting
+// into wait_for_pdp read the loaded policy from.
+func SetWaitForPDPOpaUrl(opaUrl string) {
+	globalPDPOpaUrl = opaUrl
+}
+
+// GetWaitForPDPOpaUrl returns the opa_url of the provider-level wait_for_pdp
+// block, which is empty when it is not set.
+func GetWaitForPDPOpaUrl() string {
+	return globalPDPOpaUrl
+}
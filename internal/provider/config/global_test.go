@@ -30,3 +30,15 @@ func TestGetGlobalConfigEmpty(t *testing.T) {
 		t.Errorf("GetGlobalApiKey() should be empty, got %v", got)
 	}
 }
+
+func TestSetAndGetAdoptExisting(t *testing.T) {
+	SetAdoptExisting(true)
+	if !GetAdoptExisting() {
+		t.Errorf("GetAdoptExisting() = false, want true")
+	}
+
+	SetAdoptExisting(false)
+	if GetAdoptExisting() {
+		t.Errorf("GetAdoptExisting() = true, want false")
+	}
+}
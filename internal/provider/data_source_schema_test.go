@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+// resourceOnlyAttributes only change how a resource is managed, so data
+// sources sharing a model with a resource must not expose them.
+var resourceOnlyAttributes = []string{"adopt_existing", "deletion_protection"}
+
+func TestDataSourcesOmitResourceOnlyAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	for _, newDataSource := range permitProvider.DataSources(ctx) {
+		d := newDataSource()
+
+		var metadata datasource.MetadataResponse
+		d.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: "permitio"}, &metadata)
+
+		var schemaResponse datasource.SchemaResponse
+		d.Schema(ctx, datasource.SchemaRequest{}, &schemaResponse)
+
+		for _, name := range resourceOnlyAttributes {
+			if _, ok := schemaResponse.Schema.Attributes[name]; ok {
+				t.Errorf("%s: has the resource-only attribute %s", metadata.TypeName, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package environment_export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/user_attributes"
+)
+
+type environmentExportClient struct {
+	client *permit.Client
+}
+
+// Snapshot reads the schema of the whole environment.
+func (c *environmentExportClient) Snapshot(ctx context.Context) (snapshot, error) {
+	var s snapshot
+
+	resources, err := common.ListResources(ctx, c.client, common.ResourceListOptions{Roles: true, Relations: true})
+	if err != nil {
+		return snapshot{}, err
+	}
+
+	for _, resource := range resources {
+		r := resourceSnapshotFromSDK(resource.ResourceRead)
+
+		for _, role := range resource.Roles {
+			r.Roles = append(r.Roles, roleSnapshotFromSDK(role.Key, role.Name, role.Description, role.Permissions, role.Extends))
+
+			if role.GrantedTo == nil {
+				continue
+			}
+			for _, rule := range role.GrantedTo.UsersWithRole {
+				s.RoleDerivations = append(s.RoleDerivations, derivationSnapshot{
+					Resource:   resource.Key,
+					ToRole:     role.Key,
+					OnResource: rule.OnResource,
+					Role:       rule.Role,
+					LinkedBy:   rule.LinkedByRelation,
+				})
+			}
+		}
+
+		for _, relation := range resource.Relations {
+			s.Relations = append(s.Relations, relationSnapshot{
+				ObjectResource:  resource.Key,
+				Key:             relation.Key,
+				Name:            relation.Name,
+				Description:     stringValue(relation.Description),
+				SubjectResource: relation.SubjectResource,
+			})
+		}
+
+		s.Resources = append(s.Resources, r)
+	}
+
+	userAttributes, err := common.ListAllPages(func(page int, perPage int) ([]models.ResourceAttributeRead, error) {
+		return c.client.Api.ResourceAttributes.List(ctx, user_attributes.UserKey, page, perPage)
+	})
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed listing user attributes: %w", err)
+	}
+
+	s.UserAttributes = map[string]attributeSnapshot{}
+	for _, attribute := range userAttributes {
+		s.UserAttributes[attribute.Key] = attributeSnapshot{
+			Type:        string(attribute.Type),
+			Description: stringValue(attribute.Description),
+		}
+	}
+
+	roles, err := common.ListAllPages(func(page int, perPage int) ([]models.RoleRead, error) {
+		return c.client.Api.Roles.List(ctx, page, perPage)
+	})
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed listing roles: %w", err)
+	}
+
+	for _, role := range roles {
+		s.Roles = append(s.Roles, roleSnapshotFromSDK(role.Key, role.Name, role.Description, role.Permissions, role.Extends))
+	}
+
+	conditionSets, err := common.ListAllPages(func(page int, perPage int) ([]models.ConditionSetRead, error) {
+		return c.client.Api.ConditionSets.List(ctx, page, perPage)
+	})
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed listing condition sets: %w", err)
+	}
+
+	// Autogenerated condition sets belong to the objects that generated them,
+	// and are left out together with their rules.
+	conditionSetKeys := map[string]struct{}{}
+	for _, conditionSet := range conditionSets {
+		if conditionSet.Autogenerated != nil && *conditionSet.Autogenerated {
+			continue
+		}
+		conditionSetKeys[conditionSet.Key] = struct{}{}
+
+		cs := conditionSetSnapshot{
+			Key:         conditionSet.Key,
+			Type:        string(models.USERSET),
+			Name:        conditionSet.Name,
+			Description: stringValue(conditionSet.Description),
+			Conditions:  conditionSet.Conditions,
+		}
+		if conditionSet.Type != nil {
+			cs.Type = string(*conditionSet.Type)
+		}
+		if conditionSet.Resource != nil {
+			cs.Resource = conditionSet.Resource.Key
+		}
+		s.ConditionSets = append(s.ConditionSets, cs)
+	}
+
+	rules, err := c.client.Api.ConditionSets.ListSetPermissions(ctx, "", "", "")
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed listing condition set rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		_, userSetOk := conditionSetKeys[rule.UserSet]
+		_, resourceSetOk := conditionSetKeys[rule.ResourceSet]
+		if !userSetOk || !resourceSetOk {
+			continue
+		}
+		s.ConditionSetRules = append(s.ConditionSetRules, conditionSetRuleSnapshot{
+			UserSet:     rule.UserSet,
+			Permission:  rule.Permission,
+			ResourceSet: rule.ResourceSet,
+		})
+	}
+
+	return s, nil
+}
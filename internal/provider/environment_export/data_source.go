@@ -0,0 +1,87 @@
+package environment_export
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/permit"
+)
+
+var (
+	_ datasource.DataSource              = &EnvironmentExportDataSource{}
+	_ datasource.DataSourceWithConfigure = &EnvironmentExportDataSource{}
+)
+
+func NewEnvironmentExportDataSource() datasource.DataSource {
+	return &EnvironmentExportDataSource{}
+}
+
+type EnvironmentExportDataSource struct {
+	client environmentExportClient
+}
+
+func (d *EnvironmentExportDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+	client, ok := request.ProviderData.(*permit.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *permit.Client, got: %T.", request.ProviderData),
+		)
+		return
+	}
+	d.client = environmentExportClient{client: client}
+}
+
+func (d *EnvironmentExportDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment_export"
+}
+
+func (d *EnvironmentExportDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Takes a point-in-time snapshot of the environment schema: resources with their actions, " +
+			"attributes and roles, user attributes, top-level roles, relations, role derivations, condition sets and " +
+			"condition set rules. IDs and timestamps are left out and every collection is sorted, so the snapshot only " +
+			"changes when the policy does.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "SHA-256 checksum of `json`",
+			},
+			"json": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The snapshot as an indented JSON document with stable key ordering",
+			},
+		},
+	}
+}
+
+func (d *EnvironmentExportDataSource) Read(ctx context.Context, _ datasource.ReadRequest, response *datasource.ReadResponse) {
+	s, err := d.client.Snapshot(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to Read Environment",
+			fmt.Sprintf("Unable to take a snapshot of the environment: %s", err.Error()),
+		)
+		return
+	}
+
+	document, checksum, err := s.encode()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to Encode Environment Snapshot",
+			err.Error(),
+		)
+		return
+	}
+
+	state := environmentExportModel{
+		Id:   types.StringValue(checksum),
+		Json: types.StringValue(document),
+	}
+	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,252 @@
+package environment_export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+type environmentExportModel struct {
+	Id   types.String `tfsdk:"id"`
+	Json types.String `tfsdk:"json"`
+}
+
+// snapshot is the document returned by the data source. It only holds the
+// schema of the environment: IDs and timestamps are left out so that two
+// snapshots of the same policy are byte-for-byte identical. Collections are
+// either maps, which encoding/json writes with sorted keys, or slices sorted
+// by normalize.
+type snapshot struct {
+	Resources         []resourceSnapshot           `json:"resources"`
+	UserAttributes    map[string]attributeSnapshot `json:"user_attributes"`
+	Roles             []roleSnapshot               `json:"roles"`
+	Relations         []relationSnapshot           `json:"relations"`
+	RoleDerivations   []derivationSnapshot         `json:"role_derivations"`
+	ConditionSets     []conditionSetSnapshot       `json:"condition_sets"`
+	ConditionSetRules []conditionSetRuleSnapshot   `json:"condition_set_rules"`
+}
+
+type resourceSnapshot struct {
+	Key         string                       `json:"key"`
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	Urn         string                       `json:"urn"`
+	Actions     map[string]actionSnapshot    `json:"actions"`
+	Attributes  map[string]attributeSnapshot `json:"attributes"`
+	Roles       []roleSnapshot               `json:"roles"`
+}
+
+type actionSnapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type attributeSnapshot struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type roleSnapshot struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Extends     []string `json:"extends"`
+}
+
+type relationSnapshot struct {
+	ObjectResource  string `json:"object_resource"`
+	Key             string `json:"key"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	SubjectResource string `json:"subject_resource"`
+}
+
+type derivationSnapshot struct {
+	Resource   string `json:"resource"`
+	ToRole     string `json:"to_role"`
+	OnResource string `json:"on_resource"`
+	Role       string `json:"role"`
+	LinkedBy   string `json:"linked_by"`
+}
+
+type conditionSetSnapshot struct {
+	Key         string                 `json:"key"`
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Resource    string                 `json:"resource,omitempty"`
+	Conditions  map[string]interface{} `json:"conditions"`
+}
+
+type conditionSetRuleSnapshot struct {
+	UserSet     string `json:"user_set"`
+	Permission  string `json:"permission"`
+	ResourceSet string `json:"resource_set"`
+}
+
+func resourceSnapshotFromSDK(resource models.ResourceRead) resourceSnapshot {
+	r := resourceSnapshot{
+		Key:         resource.Key,
+		Name:        resource.Name,
+		Description: stringValue(resource.Description),
+		Urn:         stringValue(resource.Urn),
+		Actions:     map[string]actionSnapshot{},
+		Attributes:  map[string]attributeSnapshot{},
+		Roles:       []roleSnapshot{},
+	}
+
+	if resource.Actions != nil {
+		for key, action := range *resource.Actions {
+			r.Actions[key] = actionSnapshot{
+				Name:        stringValue(action.Name),
+				Description: stringValue(action.Description),
+			}
+		}
+	}
+
+	if resource.Attributes != nil {
+		for key, attribute := range *resource.Attributes {
+			r.Attributes[key] = attributeSnapshot{
+				Type:        string(attribute.Type),
+				Description: stringValue(attribute.Description),
+			}
+		}
+	}
+
+	return r
+}
+
+func roleSnapshotFromSDK(key string, name string, description *string, permissions []string, extends []string) roleSnapshot {
+	return roleSnapshot{
+		Key:         key,
+		Name:        name,
+		Description: stringValue(description),
+		Permissions: sortedStrings(permissions),
+		Extends:     sortedStrings(extends),
+	}
+}
+
+// normalize sorts every slice in the snapshot and replaces nil collections
+// with empty ones, so that the encoded document does not depend on the order
+// in which the API returned objects.
+func (s *snapshot) normalize() {
+	if s.Resources == nil {
+		s.Resources = []resourceSnapshot{}
+	}
+	if s.UserAttributes == nil {
+		s.UserAttributes = map[string]attributeSnapshot{}
+	}
+	if s.Roles == nil {
+		s.Roles = []roleSnapshot{}
+	}
+	if s.Relations == nil {
+		s.Relations = []relationSnapshot{}
+	}
+	if s.RoleDerivations == nil {
+		s.RoleDerivations = []derivationSnapshot{}
+	}
+	if s.ConditionSets == nil {
+		s.ConditionSets = []conditionSetSnapshot{}
+	}
+	if s.ConditionSetRules == nil {
+		s.ConditionSetRules = []conditionSetRuleSnapshot{}
+	}
+
+	sort.Slice(s.Resources, func(i, j int) bool { return s.Resources[i].Key < s.Resources[j].Key })
+	for i := range s.Resources {
+		s.Resources[i].normalize()
+	}
+	s.Roles = normalizeRoles(s.Roles)
+
+	sort.Slice(s.Relations, func(i, j int) bool {
+		a, b := s.Relations[i], s.Relations[j]
+		if a.ObjectResource != b.ObjectResource {
+			return a.ObjectResource < b.ObjectResource
+		}
+		return a.Key < b.Key
+	})
+
+	sort.Slice(s.RoleDerivations, func(i, j int) bool {
+		return lessStrings(derivationSortKey(s.RoleDerivations[i]), derivationSortKey(s.RoleDerivations[j]))
+	})
+
+	sort.Slice(s.ConditionSets, func(i, j int) bool { return s.ConditionSets[i].Key < s.ConditionSets[j].Key })
+	for i := range s.ConditionSets {
+		if s.ConditionSets[i].Conditions == nil {
+			s.ConditionSets[i].Conditions = map[string]interface{}{}
+		}
+	}
+
+	sort.Slice(s.ConditionSetRules, func(i, j int) bool {
+		a, b := s.ConditionSetRules[i], s.ConditionSetRules[j]
+		return lessStrings([]string{a.UserSet, a.Permission, a.ResourceSet}, []string{b.UserSet, b.Permission, b.ResourceSet})
+	})
+}
+
+// encode normalizes the snapshot and returns it as indented JSON along with
+// its SHA-256 checksum.
+func (s *snapshot) encode() (string, string, error) {
+	s.normalize()
+
+	document, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+
+	checksum := sha256.Sum256(document)
+	return string(document), hex.EncodeToString(checksum[:]), nil
+}
+
+func (r *resourceSnapshot) normalize() {
+	if r.Actions == nil {
+		r.Actions = map[string]actionSnapshot{}
+	}
+	if r.Attributes == nil {
+		r.Attributes = map[string]attributeSnapshot{}
+	}
+	r.Roles = normalizeRoles(r.Roles)
+}
+
+func normalizeRoles(roles []roleSnapshot) []roleSnapshot {
+	if roles == nil {
+		return []roleSnapshot{}
+	}
+
+	for i := range roles {
+		roles[i].Permissions = sortedStrings(roles[i].Permissions)
+		roles[i].Extends = sortedStrings(roles[i].Extends)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Key < roles[j].Key })
+	return roles
+}
+
+func derivationSortKey(d derivationSnapshot) []string {
+	return []string{d.Resource, d.ToRole, d.OnResource, d.Role, d.LinkedBy}
+}
+
+func lessStrings(a []string, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
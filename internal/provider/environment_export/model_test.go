@@ -0,0 +1,107 @@
+package environment_export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnapshotEncodeIsStable(t *testing.T) {
+	build := func(reverse bool) snapshot {
+		s := snapshot{
+			Resources: []resourceSnapshot{
+				{Key: "folder", Actions: map[string]actionSnapshot{"read": {}}, Roles: []roleSnapshot{}},
+				{Key: "document", Actions: map[string]actionSnapshot{"write": {}, "read": {}}, Roles: []roleSnapshot{
+					{Key: "viewer", Permissions: []string{"read"}},
+					{Key: "editor", Permissions: []string{"write", "read"}, Extends: []string{"viewer"}},
+				}},
+			},
+			Relations: []relationSnapshot{
+				{ObjectResource: "document", Key: "parent", SubjectResource: "folder"},
+				{ObjectResource: "document", Key: "owner", SubjectResource: "user"},
+			},
+			RoleDerivations: []derivationSnapshot{
+				{Resource: "document", ToRole: "viewer", OnResource: "folder", Role: "viewer", LinkedBy: "parent"},
+				{Resource: "document", ToRole: "editor", OnResource: "folder", Role: "editor", LinkedBy: "parent"},
+			},
+			ConditionSetRules: []conditionSetRuleSnapshot{
+				{UserSet: "members", Permission: "document:read", ResourceSet: "public"},
+				{UserSet: "admins", Permission: "document:write", ResourceSet: "public"},
+			},
+		}
+		if reverse {
+			reverseSlice(s.Resources)
+			reverseSlice(s.Resources[1].Roles)
+			reverseSlice(s.Relations)
+			reverseSlice(s.RoleDerivations)
+			reverseSlice(s.ConditionSetRules)
+		}
+		return s
+	}
+
+	ordered, reversed := build(false), build(true)
+
+	first, firstChecksum, err := ordered.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	second, secondChecksum, err := reversed.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	if first != second {
+		t.Errorf("encode() depends on input order:\n%s\n---\n%s", first, second)
+	}
+	if firstChecksum != secondChecksum {
+		t.Errorf("encode() checksums differ: %s != %s", firstChecksum, secondChecksum)
+	}
+
+	if !strings.Contains(first, `"attributes": {}`) || strings.Contains(first, "null") {
+		t.Errorf("encode() left nil collections in the output:\n%s", first)
+	}
+
+	fragments := []string{`"key": "document"`, `"key": "editor"`, `"read"`, `"write"`, `"key": "viewer"`, `"key": "folder"`, `"key": "owner"`, `"key": "parent"`, `"user_set": "admins"`}
+	last := -1
+	for _, fragment := range fragments {
+		index := strings.Index(first[last+1:], fragment)
+		if index < 0 {
+			t.Fatalf("encode() output is missing %s after offset %d:\n%s", fragment, last, first)
+		}
+		last += index + 1
+	}
+}
+
+func TestSnapshotEncodeEmpty(t *testing.T) {
+	s := snapshot{}
+	document, _, err := s.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	for _, field := range []string{`"resources": []`, `"user_attributes": {}`, `"roles": []`, `"relations": []`, `"role_derivations": []`, `"condition_sets": []`, `"condition_set_rules": []`} {
+		if !strings.Contains(document, field) {
+			t.Errorf("encode() of an empty snapshot = %s, want it to contain %s", document, field)
+		}
+	}
+}
+
+func TestRoleSnapshotSortsPermissions(t *testing.T) {
+	permissions := []string{"document:write", "document:read"}
+	role := roleSnapshotFromSDK("editor", "Editor", nil, permissions, nil)
+
+	if got := strings.Join(role.Permissions, ","); got != "document:read,document:write" {
+		t.Errorf("Permissions = %s, want document:read,document:write", got)
+	}
+	if permissions[0] != "document:write" {
+		t.Errorf("roleSnapshotFromSDK() modified its input: %v", permissions)
+	}
+	if role.Extends == nil || len(role.Extends) != 0 {
+		t.Errorf("Extends = %#v, want an empty slice", role.Extends)
+	}
+}
+
+func reverseSlice[T any](values []T) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
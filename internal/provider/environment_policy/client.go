@@ -0,0 +1,134 @@
+package environment_policy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/user_attributes"
+)
+
+type environmentPolicyClient struct {
+	client *permit.Client
+}
+
+// List returns every object of the kinds enabled in the policy that exists in
+// the environment. Built-in resources and autogenerated condition sets are
+// left out, since they are not created by users.
+func (c *environmentPolicyClient) List(ctx context.Context, p policy) ([]object, error) {
+	var objects []object
+
+	needResources := p.enabled[kindResource] || p.enabled[kindRole] || p.enabled[kindRelation]
+
+	var resources []common.ResourceListing
+	if needResources {
+		var err error
+		resources, err = common.ListResources(ctx, c.client, common.ResourceListOptions{
+			Roles:     p.enabled[kindRole],
+			Relations: p.enabled[kindRelation],
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.enabled[kindResource] {
+		for _, resource := range resources {
+			objects = append(objects, object{Kind: kindResource, Key: resource.Key})
+		}
+	}
+
+	if p.enabled[kindRole] {
+		roles, err := common.ListAllPages(func(page int, perPage int) ([]models.RoleRead, error) {
+			return c.client.Api.Roles.List(ctx, page, perPage)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, role := range roles {
+			objects = append(objects, object{Kind: kindRole, Key: role.Key})
+		}
+
+		for _, resource := range resources {
+			for _, role := range resource.Roles {
+				objects = append(objects, object{Kind: kindRole, Key: resource.Key + ":" + role.Key})
+			}
+		}
+	}
+
+	if p.enabled[kindRelation] {
+		for _, resource := range resources {
+			for _, relation := range resource.Relations {
+				objects = append(objects, object{Kind: kindRelation, Key: resource.Key + ":" + relation.Key})
+			}
+		}
+	}
+
+	if p.enabled[kindConditionSet] {
+		conditionSets, err := common.ListAllPages(func(page int, perPage int) ([]models.ConditionSetRead, error) {
+			return c.client.Api.ConditionSets.List(ctx, page, perPage)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, conditionSet := range conditionSets {
+			if conditionSet.Autogenerated != nil && *conditionSet.Autogenerated {
+				continue
+			}
+			objects = append(objects, object{Kind: kindConditionSet, Key: conditionSet.Key})
+		}
+	}
+
+	if p.enabled[kindUserAttribute] {
+		attributes, err := common.ListAllPages(func(page int, perPage int) ([]models.ResourceAttributeRead, error) {
+			return c.client.Api.ResourceAttributes.List(ctx, user_attributes.UserKey, page, perPage)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, attribute := range attributes {
+			objects = append(objects, object{Kind: kindUserAttribute, Key: attribute.Key})
+		}
+	}
+
+	return objects, nil
+}
+
+// Delete removes an object from the environment. Objects that are already
+// gone, for example because their resource was deleted first, are skipped.
+func (c *environmentPolicyClient) Delete(ctx context.Context, o object) error {
+	var err error
+
+	switch o.Kind {
+	case kindResource:
+		err = c.client.Api.Resources.Delete(ctx, o.Key)
+	case kindRole:
+		if resource, role, ok := strings.Cut(o.Key, ":"); ok {
+			err = c.client.Api.ResourceRoles.Delete(ctx, resource, role)
+		} else {
+			err = c.client.Api.Roles.Delete(ctx, o.Key)
+		}
+	case kindRelation:
+		resource, relation, _ := strings.Cut(o.Key, ":")
+		err = c.client.Api.ResourceRelations.Delete(ctx, resource, relation)
+	case kindConditionSet:
+		err = c.client.Api.ConditionSets.Delete(ctx, o.Key)
+	case kindUserAttribute:
+		err = c.client.Api.ResourceAttributes.Delete(ctx, user_attributes.UserKey, o.Key)
+	}
+
+	if common.IsNotFoundErr(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,139 @@
+package environment_policy
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	kindResource      = "resource"
+	kindRole          = "role"
+	kindConditionSet  = "condition_set"
+	kindRelation      = "relation"
+	kindUserAttribute = "user_attribute"
+)
+
+// deletionOrder deletes objects that live under a resource before the
+// resource itself, so that cascades do not turn later deletions into errors.
+var deletionOrder = map[string]int{
+	kindRelation:      0,
+	kindRole:          1,
+	kindConditionSet:  2,
+	kindUserAttribute: 3,
+	kindResource:      4,
+}
+
+type environmentPolicyModel struct {
+	Id                    types.String `tfsdk:"id"`
+	Resources             types.Bool   `tfsdk:"resources"`
+	Roles                 types.Bool   `tfsdk:"roles"`
+	ConditionSets         types.Bool   `tfsdk:"condition_sets"`
+	Relations             types.Bool   `tfsdk:"relations"`
+	UserAttributes        types.Bool   `tfsdk:"user_attributes"`
+	ManagedResources      types.Set    `tfsdk:"managed_resources"`
+	ManagedRoles          types.Set    `tfsdk:"managed_roles"`
+	ManagedConditionSets  types.Set    `tfsdk:"managed_condition_sets"`
+	ManagedRelations      types.Set    `tfsdk:"managed_relations"`
+	ManagedUserAttributes types.Set    `tfsdk:"managed_user_attributes"`
+	UnmanagedObjects      types.Set    `tfsdk:"unmanaged_objects"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// object identifies a schema object in the environment. Roles and relations
+// that belong to a resource are keyed as "resource:key".
+type object struct {
+	Kind string
+	Key  string
+}
+
+func (o object) String() string {
+	return o.Kind + ":" + o.Key
+}
+
+func parseObject(s string) (object, bool) {
+	kind, key, ok := strings.Cut(s, ":")
+	if !ok {
+		return object{}, false
+	}
+	if _, known := deletionOrder[kind]; !known {
+		return object{}, false
+	}
+	return object{Kind: kind, Key: key}, true
+}
+
+// policy is the resolved configuration: which kinds are enforced and which
+// keys of each kind are managed elsewhere.
+type policy struct {
+	enabled map[string]bool
+	managed map[string]map[string]struct{}
+}
+
+func policyFromModel(ctx context.Context, m environmentPolicyModel) (policy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	p := policy{
+		enabled: map[string]bool{
+			kindResource:      m.Resources.ValueBool(),
+			kindRole:          m.Roles.ValueBool(),
+			kindConditionSet:  m.ConditionSets.ValueBool(),
+			kindRelation:      m.Relations.ValueBool(),
+			kindUserAttribute: m.UserAttributes.ValueBool(),
+		},
+		managed: map[string]map[string]struct{}{},
+	}
+
+	for kind, keys := range map[string]types.Set{
+		kindResource:      m.ManagedResources,
+		kindRole:          m.ManagedRoles,
+		kindConditionSet:  m.ManagedConditionSets,
+		kindRelation:      m.ManagedRelations,
+		kindUserAttribute: m.ManagedUserAttributes,
+	} {
+		var elements []string
+		diags.Append(keys.ElementsAs(ctx, &elements, false)...)
+
+		p.managed[kind] = make(map[string]struct{}, len(elements))
+		for _, key := range elements {
+			p.managed[kind][key] = struct{}{}
+		}
+	}
+
+	return p, diags
+}
+
+func (p policy) isUnmanaged(o object) bool {
+	if !p.enabled[o.Kind] {
+		return false
+	}
+	_, ok := p.managed[o.Kind][o.Key]
+	return !ok
+}
+
+// unmanaged filters objects down to the ones the policy would delete, in the
+// order they should be deleted.
+func (p policy) unmanaged(objects []object) []object {
+	var result []object
+	for _, o := range objects {
+		if p.isUnmanaged(o) {
+			result = append(result, o)
+		}
+	}
+
+	sortObjects(result)
+	return result
+}
+
+func sortObjects(objects []object) {
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Kind != objects[j].Kind {
+			return deletionOrder[objects[i].Kind] < deletionOrder[objects[j].Kind]
+		}
+		return objects[i].Key < objects[j].Key
+	})
+}
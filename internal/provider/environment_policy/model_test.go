@@ -0,0 +1,85 @@
+package environment_policy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func stringSet(values ...string) types.Set {
+	elements := make([]attr.Value, len(values))
+	for i, v := range values {
+		elements[i] = types.StringValue(v)
+	}
+	return types.SetValueMust(types.StringType, elements)
+}
+
+func TestParseObject(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   object
+		wantOk bool
+	}{
+		{"resource:document", object{Kind: kindResource, Key: "document"}, true},
+		{"role:document:editor", object{Kind: kindRole, Key: "document:editor"}, true},
+		{"relation:folder:parent", object{Kind: kindRelation, Key: "folder:parent"}, true},
+		{"tenant:default", object{}, false},
+		{"document", object{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseObject(tt.in)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("parseObject(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.wantOk)
+		}
+		if ok && got.String() != tt.in {
+			t.Errorf("parseObject(%q).String() = %q", tt.in, got.String())
+		}
+	}
+}
+
+func TestPolicyUnmanaged(t *testing.T) {
+	model := environmentPolicyModel{
+		Resources:             types.BoolValue(true),
+		Roles:                 types.BoolValue(true),
+		ConditionSets:         types.BoolValue(false),
+		Relations:             types.BoolValue(true),
+		UserAttributes:        types.BoolValue(false),
+		ManagedResources:      stringSet("document"),
+		ManagedRoles:          stringSet("admin", "document:editor"),
+		ManagedConditionSets:  stringSet(),
+		ManagedRelations:      stringSet(),
+		ManagedUserAttributes: stringSet(),
+	}
+
+	p, diags := policyFromModel(context.Background(), model)
+	if diags.HasError() {
+		t.Fatalf("policyFromModel() diagnostics: %v", diags)
+	}
+
+	objects := []object{
+		{Kind: kindResource, Key: "document"},
+		{Kind: kindResource, Key: "folder"},
+		{Kind: kindRole, Key: "admin"},
+		{Kind: kindRole, Key: "viewer"},
+		{Kind: kindRole, Key: "document:editor"},
+		{Kind: kindRole, Key: "folder:owner"},
+		{Kind: kindRelation, Key: "folder:parent"},
+		{Kind: kindConditionSet, Key: "us_employees"},
+		{Kind: kindUserAttribute, Key: "department"},
+	}
+
+	want := []object{
+		{Kind: kindRelation, Key: "folder:parent"},
+		{Kind: kindRole, Key: "folder:owner"},
+		{Kind: kindRole, Key: "viewer"},
+		{Kind: kindResource, Key: "folder"},
+	}
+
+	if got := p.unmanaged(objects); !reflect.DeepEqual(got, want) {
+		t.Errorf("unmanaged() = %v, want %v", got, want)
+	}
+}
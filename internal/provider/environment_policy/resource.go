@@ -0,0 +1,265 @@
+package environment_policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+const environmentPolicyId = "environment_policy"
+
+var (
+	_ resource.Resource               = &EnvironmentPolicyResource{}
+	_ resource.ResourceWithConfigure  = &EnvironmentPolicyResource{}
+	_ resource.ResourceWithModifyPlan = &EnvironmentPolicyResource{}
+)
+
+func NewEnvironmentPolicyResource() resource.Resource {
+	return &EnvironmentPolicyResource{}
+}
+
+type EnvironmentPolicyResource struct {
+	client environmentPolicyClient
+}
+
+func (r *EnvironmentPolicyResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	permitClient := common.Configure(ctx, request, response)
+	r.client = environmentPolicyClient{client: permitClient}
+}
+
+func (r *EnvironmentPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment_policy"
+}
+
+func scopeAttribute(description string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+}
+
+func managedKeysAttribute(description string) schema.SetAttribute {
+	return schema.SetAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+	}
+}
+
+func (r *EnvironmentPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Makes the configuration authoritative for the environment. For every enabled scope, objects that exist in the environment " +
+			"but are not listed in the matching `managed_*` set are reported in `unmanaged_objects` during refresh, and deleted on the next apply. " +
+			"Objects found while creating this resource are only deleted by a later apply, after they have been shown in a plan. " +
+			"Usually the `managed_*` sets are built from the keys of the resources managed elsewhere in the configuration.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"resources":         scopeAttribute("Whether resources not listed in `managed_resources` are deleted. Defaults to `false`."),
+			"roles":             scopeAttribute("Whether roles not listed in `managed_roles` are deleted. Defaults to `false`."),
+			"condition_sets":    scopeAttribute("Whether user sets and resource sets not listed in `managed_condition_sets` are deleted. Defaults to `false`."),
+			"relations":         scopeAttribute("Whether relations not listed in `managed_relations` are deleted. Defaults to `false`."),
+			"user_attributes":   scopeAttribute("Whether user attributes not listed in `managed_user_attributes` are deleted. Defaults to `false`."),
+			"managed_resources": managedKeysAttribute("Keys of the resources that are managed elsewhere."),
+			"managed_roles": managedKeysAttribute("Keys of the roles that are managed elsewhere. " +
+				"Resource roles are given as `resource:role`."),
+			"managed_condition_sets":  managedKeysAttribute("Keys of the user sets and resource sets that are managed elsewhere."),
+			"managed_relations":       managedKeysAttribute("Keys of the relations that are managed elsewhere, given as `object_resource:relation`."),
+			"managed_user_attributes": managedKeysAttribute("Keys of the user attributes that are managed elsewhere."),
+			"unmanaged_objects": schema.SetAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Objects within the enabled scopes that are not managed, as `kind:key`. " +
+					"A plan that shows entries removed from this set deletes those objects from the environment.",
+				Computed: true,
+				Default:  setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
+	}
+}
+
+// ModifyPlan lists the objects the next apply is going to delete.
+func (r *EnvironmentPolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan environmentPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !isKnown(plan) {
+		return
+	}
+
+	toDelete, diags := pendingDeletions(ctx, state, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || len(toDelete) == 0 {
+		return
+	}
+
+	descriptions := make([]string, len(toDelete))
+	for i, o := range toDelete {
+		descriptions[i] = o.String()
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Unmanaged objects will be deleted",
+		fmt.Sprintf("Applying this plan deletes %s from the environment: %s",
+			common.Pluralize(len(toDelete), "unmanaged object"), strings.Join(descriptions, ", ")),
+	)
+}
+
+func (r *EnvironmentPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan environmentPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(environmentPolicyId)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *EnvironmentPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state environmentPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p, diags := policyFromModel(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+
+	objects, err := r.client.List(ctx, p)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read environment policy",
+			fmt.Sprintf("Unable to list the objects in the environment: %s", err),
+		)
+		return
+	}
+
+	unmanaged := p.unmanaged(objects)
+	keys := make([]string, len(unmanaged))
+	for i, o := range unmanaged {
+		keys[i] = o.String()
+	}
+
+	unmanagedValue, diags := types.SetValueFrom(ctx, types.StringType, keys)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.UnmanagedObjects = unmanagedValue
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *EnvironmentPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan environmentPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+
+	toDelete, diags := pendingDeletions(ctx, state, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, o := range toDelete {
+		tflog.Info(ctx, fmt.Sprintf("Deleting unmanaged %s", o))
+
+		if err := r.client.Delete(ctx, o); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to delete unmanaged object",
+				fmt.Sprintf("Unable to delete %s: %s", o, err),
+			)
+			return
+		}
+	}
+
+	plan.Id = types.StringValue(environmentPolicyId)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete only removes the policy from state; the environment is left as is.
+func (r *EnvironmentPolicyResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+// pendingDeletions returns the unmanaged objects found during the last refresh
+// that are still unmanaged under the planned configuration.
+func pendingDeletions(ctx context.Context, state environmentPolicyModel, plan environmentPolicyModel) ([]object, diag.Diagnostics) {
+	var (
+		diags diag.Diagnostics
+		keys  []string
+	)
+
+	diags.Append(state.UnmanagedObjects.ElementsAs(ctx, &keys, false)...)
+
+	p, d := policyFromModel(ctx, plan)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var objects []object
+	for _, key := range keys {
+		if o, ok := parseObject(key); ok {
+			objects = append(objects, o)
+		}
+	}
+
+	return p.unmanaged(objects), diags
+}
+
+// isKnown reports whether every scope and managed set in the plan is known,
+// which is needed to tell what the apply will delete.
+func isKnown(m environmentPolicyModel) bool {
+	for _, value := range []attr.Value{
+		m.Resources, m.Roles, m.ConditionSets, m.Relations, m.UserAttributes,
+		m.ManagedResources, m.ManagedRoles, m.ManagedConditionSets, m.ManagedRelations, m.ManagedUserAttributes,
+	} {
+		if value.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
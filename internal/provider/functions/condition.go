@@ -0,0 +1,194 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ function.Function = &ConditionFunction{}
+	_ function.Function = &ConditionGroupFunction{}
+)
+
+func NewConditionFunction() function.Function {
+	return &ConditionFunction{}
+}
+
+// ConditionFunction builds a single condition of a user set or resource set,
+// encoded as JSON so that it can be combined with all_of and any_of or passed
+// to the conditions attribute as is.
+type ConditionFunction struct{}
+
+func (f *ConditionFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "condition"
+}
+
+func (f *ConditionFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build a condition of a user set or resource set",
+		MarkdownDescription: "Returns the JSON condition `{\"<attribute>\": {\"<operator>\": <value>}}`, " +
+			"to combine with `all_of` and `any_of` into the `conditions` of a `permitio_user_set` or `permitio_resource_set`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "attribute",
+				MarkdownDescription: "The attribute the condition tests, for example `subject.email` or `resource.title`",
+			},
+			function.StringParameter{
+				Name:                "operator",
+				MarkdownDescription: "The operator, for example `equals`, `contains` or `in`",
+			},
+			function.DynamicParameter{
+				Name:                "value",
+				MarkdownDescription: "The value the attribute is compared to: a string, number, bool, or a list of them",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ConditionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		attribute, operator string
+		value               types.Dynamic
+	)
+
+	resp.Error = req.Arguments.Get(ctx, &attribute, &operator, &value)
+	if resp.Error != nil {
+		return
+	}
+
+	if attribute == "" {
+		resp.Error = function.NewArgumentFuncError(0, "The attribute must not be empty")
+		return
+	}
+
+	if operator == "" {
+		resp.Error = function.NewArgumentFuncError(1, "The operator must not be empty")
+		return
+	}
+
+	jsonValue, err := toJSONValue(value.UnderlyingValue())
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, err.Error())
+		return
+	}
+
+	condition, err := json.Marshal(map[string]map[string]any{attribute: {operator: jsonValue}})
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, string(condition))
+}
+
+// toJSONValue converts a Terraform value to the value it is encoded as in a
+// condition.
+func toJSONValue(value attr.Value) (any, error) {
+	if value == nil || value.IsNull() || value.IsUnknown() {
+		return nil, fmt.Errorf("the value must be known and not null")
+	}
+
+	var elements []attr.Value
+
+	switch v := value.(type) {
+	case types.String:
+		return v.ValueString(), nil
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.Number:
+		return json.Number(v.ValueBigFloat().Text('f', -1)), nil
+	case types.List:
+		elements = v.Elements()
+	case types.Set:
+		elements = v.Elements()
+	case types.Tuple:
+		elements = v.Elements()
+	default:
+		return nil, fmt.Errorf("the value must be a string, number, bool or a list of them, got %s", value.Type(context.Background()))
+	}
+
+	result := make([]any, len(elements))
+	for i, element := range elements {
+		switch element.(type) {
+		case types.List, types.Set, types.Tuple:
+			return nil, fmt.Errorf("the value must not contain nested lists")
+		}
+
+		converted, err := toJSONValue(element)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = converted
+	}
+
+	return result, nil
+}
+
+func NewAllOfFunction() function.Function {
+	return &ConditionGroupFunction{name: "all_of", operator: "allOf", logic: "all"}
+}
+
+func NewAnyOfFunction() function.Function {
+	return &ConditionGroupFunction{name: "any_of", operator: "anyOf", logic: "any"}
+}
+
+// ConditionGroupFunction combines conditions with the allOf or anyOf logic of
+// user set and resource set conditions.
+type ConditionGroupFunction struct {
+	name     string
+	operator string
+	logic    string
+}
+
+func (f *ConditionGroupFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *ConditionGroupFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: fmt.Sprintf("Require %s of a list of conditions", f.logic),
+		MarkdownDescription: fmt.Sprintf("Returns the JSON condition `{\"%s\": [...]}`, matching when %s of the conditions match. ", f.operator, f.logic) +
+			"The conditions are built with `condition`, `all_of` and `any_of`, and the result can be nested further or used as the `conditions` of a `permitio_user_set` or `permitio_resource_set`.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "conditions",
+				ElementType:         types.StringType,
+				MarkdownDescription: "The JSON conditions to combine",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ConditionGroupFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var conditions []string
+
+	resp.Error = req.Arguments.Get(ctx, &conditions)
+	if resp.Error != nil {
+		return
+	}
+
+	group := make([]json.RawMessage, len(conditions))
+	for i, condition := range conditions {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(condition), &object); err != nil {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Condition %d is not a JSON object: %s", i, err))
+			return
+		}
+		group[i] = json.RawMessage(condition)
+	}
+
+	combined, err := json.Marshal(map[string][]json.RawMessage{f.operator: group})
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, string(combined))
+}
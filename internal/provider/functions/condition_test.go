@@ -0,0 +1,75 @@
+package functions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestConditionFunction(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   attr.Value
+		want    string
+		wantErr bool
+	}{
+		{"string", types.StringValue("@admin.com"), `{"subject.email":{"contains":"@admin.com"}}`, false},
+		{"number", types.NumberValue(big.NewFloat(10)), `{"subject.email":{"contains":10}}`, false},
+		{"decimal", types.NumberValue(big.NewFloat(0.5)), `{"subject.email":{"contains":0.5}}`, false},
+		{"bool", types.BoolValue(true), `{"subject.email":{"contains":true}}`, false},
+		{
+			"list",
+			types.TupleValueMust([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("a"), types.StringValue("b")}),
+			`{"subject.email":{"contains":["a","b"]}}`,
+			false,
+		},
+		{"null", types.StringNull(), "", true},
+		{
+			"nested list",
+			types.TupleValueMust(
+				[]attr.Type{types.ListType{ElemType: types.StringType}},
+				[]attr.Value{types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a")})},
+			),
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runFunction(NewConditionFunction(), types.StringUnknown(),
+				types.StringValue("subject.email"), types.StringValue("contains"), types.DynamicValue(tt.value))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("condition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("condition() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionGroupFunctions(t *testing.T) {
+	conditions := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue(`{"subject.email": {"contains": "@admin.com"}}`),
+		types.StringValue(`{"anyOf":[{"subject.role":{"equals":"owner"}}]}`),
+	})
+
+	got, err := runFunction(NewAllOfFunction(), types.StringUnknown(), conditions)
+	want := `{"allOf":[{"subject.email":{"contains":"@admin.com"}},{"anyOf":[{"subject.role":{"equals":"owner"}}]}]}`
+	if err != nil || !got.Equal(types.StringValue(want)) {
+		t.Errorf("all_of() = %v, %v, want %s", got, err, want)
+	}
+
+	got, err = runFunction(NewAnyOfFunction(), types.StringUnknown(), types.ListValueMust(types.StringType, []attr.Value{}))
+	if err != nil || !got.Equal(types.StringValue(`{"anyOf":[]}`)) {
+		t.Errorf("any_of([]) = %v, %v, want {\"anyOf\":[]}", got, err)
+	}
+
+	invalid := types.ListValueMust(types.StringType, []attr.Value{types.StringValue(`["not", "an", "object"]`)})
+	if _, err := runFunction(NewAllOfFunction(), types.StringUnknown(), invalid); err == nil {
+		t.Error("all_of() of a condition that is not a JSON object should fail")
+	}
+}
@@ -0,0 +1,130 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &ImportIDFunction{}
+
+// ImportIDFunction builds the import ID of a resource whose ImportState
+// expects several keys joined by a separator.
+type ImportIDFunction struct {
+	resourceType string
+	parts        []string
+	separator    string
+
+	// optionalFirst marks the first part as optional, dropping it and its
+	// separator when empty, as for top-level roles.
+	optionalFirst bool
+
+	// lastSplit marks IDs that are split at the last separator, so that only
+	// the last part must not contain it.
+	lastSplit bool
+}
+
+// ImportIDFunctions returns the import ID builders of the resources whose ID
+// is not just their key.
+func ImportIDFunctions() []func() function.Function {
+	specs := []ImportIDFunction{
+		{resourceType: "role", parts: []string{"resource", "role"}, separator: ":", optionalFirst: true},
+		{resourceType: "relation", parts: []string{"object_resource", "relation"}, separator: ":"},
+		{resourceType: "role_derivation", parts: []string{"resource", "to_role", "on_resource", "role", "linked_by"}, separator: ":"},
+		{resourceType: "condition_set_rule", parts: []string{"user_set", "permission", "resource_set"}, separator: ","},
+		{resourceType: "resource_instance", parts: []string{"resource", "instance"}, separator: ":"},
+		{resourceType: "role_assignment", parts: []string{"user", "role", "tenant"}, separator: ":"},
+		{resourceType: "resource_instance_role_assignment", parts: []string{"user", "role", "resource", "resource_instance", "tenant"}, separator: ":"},
+		{resourceType: "group_resource_instance_role_assignment", parts: []string{"group", "role", "resource", "resource_instance", "tenant"}, separator: ":"},
+		{resourceType: "user_roles", parts: []string{"user", "tenant"}, separator: ":", lastSplit: true},
+	}
+
+	functions := make([]func() function.Function, len(specs))
+	for i, spec := range specs {
+		functions[i] = func() function.Function {
+			f := spec
+			return &f
+		}
+	}
+
+	return functions
+}
+
+func (f *ImportIDFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.resourceType + "_import_id"
+}
+
+func (f *ImportIDFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	description := fmt.Sprintf("Returns the ID to import a `permitio_%s` with, in the format `%s`.", f.resourceType, strings.Join(f.parts, f.separator))
+	if f.optionalFirst {
+		description += fmt.Sprintf(" When `%s` is empty, the ID is just the `%s`.", f.parts[0], f.parts[1])
+	}
+
+	parameters := make([]function.Parameter, len(f.parts))
+	for i, part := range f.parts {
+		parameters[i] = function.StringParameter{
+			Name:                part,
+			MarkdownDescription: fmt.Sprintf("The %s key", strings.ReplaceAll(part, "_", " ")),
+		}
+	}
+
+	resp.Definition = function.Definition{
+		Summary:             fmt.Sprintf("Build the import ID of a permitio_%s", f.resourceType),
+		MarkdownDescription: description,
+		Parameters:          parameters,
+		Return:              function.StringReturn{},
+	}
+}
+
+func (f *ImportIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	values := make([]string, len(f.parts))
+	targets := make([]any, len(f.parts))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	resp.Error = req.Arguments.Get(ctx, targets...)
+	if resp.Error != nil {
+		return
+	}
+
+	if f.optionalFirst && values[0] == "" {
+		values = values[1:]
+		if err := validateKeyPart(1, f.parts[1], values[0], f.separator); err != nil {
+			resp.Error = err
+			return
+		}
+		resp.Error = resp.Result.Set(ctx, values[0])
+		return
+	}
+
+	for i, value := range values {
+		separator := f.separator
+		if f.lastSplit && i < len(values)-1 {
+			separator = ""
+		}
+
+		if err := validateKeyPart(int64(i), f.parts[i], value, separator); err != nil {
+			resp.Error = err
+			return
+		}
+	}
+
+	resp.Error = resp.Result.Set(ctx, strings.Join(values, f.separator))
+}
+
+// validateKeyPart checks that a key joined into a larger ID is not empty and
+// does not contain the separator, which would make the ID ambiguous.
+func validateKeyPart(position int64, name, value, separator string) *function.FuncError {
+	if value == "" {
+		return function.NewArgumentFuncError(position, fmt.Sprintf("The %s must not be empty", name))
+	}
+
+	if separator != "" && strings.Contains(value, separator) {
+		return function.NewArgumentFuncError(position, fmt.Sprintf("The %s %q must not contain %q", name, value, separator))
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func importIDFunction(t *testing.T, name string) function.Function {
+	t.Helper()
+
+	for _, newFunction := range ImportIDFunctions() {
+		f := newFunction()
+		resp := &function.MetadataResponse{}
+		f.Metadata(context.Background(), function.MetadataRequest{}, resp)
+		if resp.Name == name {
+			return f
+		}
+	}
+
+	t.Fatalf("no function named %s", name)
+	return nil
+}
+
+func TestImportIDFunctions(t *testing.T) {
+	tests := []struct {
+		function string
+		parts    []string
+		want     string
+		wantErr  bool
+	}{
+		{"role_assignment_import_id", []string{"john@example.com", "admin", "default"}, "john@example.com:admin:default", false},
+		{"role_assignment_import_id", []string{"john", "admin", ""}, "", true},
+		{"role_assignment_import_id", []string{"john:doe", "admin", "default"}, "", true},
+		{"role_import_id", []string{"document", "editor"}, "document:editor", false},
+		{"role_import_id", []string{"", "admin"}, "admin", false},
+		{"role_import_id", []string{"", ""}, "", true},
+		{"condition_set_rule_import_id", []string{"admins", "document:read", "secret_docs"}, "admins,document:read,secret_docs", false},
+		{"condition_set_rule_import_id", []string{"admins", "document,read", "secret_docs"}, "", true},
+		{"resource_instance_role_assignment_import_id", []string{"john", "viewer", "document", "doc-1", "default"}, "john:viewer:document:doc-1:default", false},
+		{"user_roles_import_id", []string{"urn:user:john", "default"}, "urn:user:john:default", false},
+		{"user_roles_import_id", []string{"john", "de:fault"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			arguments := make([]attr.Value, len(tt.parts))
+			for i, part := range tt.parts {
+				arguments[i] = types.StringValue(part)
+			}
+
+			got, err := runFunction(importIDFunction(t, tt.function), types.StringUnknown(), arguments...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s%v error = %v, wantErr %v", tt.function, tt.parts, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("%s%v = %v, want %q", tt.function, tt.parts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportIDFunctionsMatchParameters(t *testing.T) {
+	for _, newFunction := range ImportIDFunctions() {
+		f := newFunction()
+		metadata := &function.MetadataResponse{}
+		f.Metadata(context.Background(), function.MetadataRequest{}, metadata)
+
+		definition := &function.DefinitionResponse{}
+		f.Definition(context.Background(), function.DefinitionRequest{}, definition)
+		if len(definition.Definition.Parameters) < 2 {
+			t.Errorf("%s has %d parameters, want at least 2", metadata.Name, len(definition.Definition.Parameters))
+		}
+	}
+}
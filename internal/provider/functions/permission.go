@@ -0,0 +1,125 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ function.Function = &PermissionFunction{}
+	_ function.Function = &ParsePermissionFunction{}
+)
+
+var permissionAttributeTypes = map[string]attr.Type{
+	"resource": types.StringType,
+	"action":   types.StringType,
+}
+
+func NewPermissionFunction() function.Function {
+	return &PermissionFunction{}
+}
+
+// PermissionFunction builds a permission in the "resource:action" format used
+// by roles, condition set rules and data sources.
+type PermissionFunction struct{}
+
+func (f *PermissionFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "permission"
+}
+
+func (f *PermissionFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Build a permission from a resource and an action",
+		MarkdownDescription: "Returns the permission `resource:action`, as used in the `permissions` of roles and the `permission` of condition set rules.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "resource",
+				MarkdownDescription: "The resource key",
+			},
+			function.StringParameter{
+				Name:                "action",
+				MarkdownDescription: "The action key",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PermissionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var resource, action string
+
+	resp.Error = req.Arguments.Get(ctx, &resource, &action)
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateKeyPart(0, "resource", resource, ":"); err != nil {
+		resp.Error = err
+		return
+	}
+
+	if err := validateKeyPart(1, "action", action, ":"); err != nil {
+		resp.Error = err
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, resource+":"+action)
+}
+
+func NewParsePermissionFunction() function.Function {
+	return &ParsePermissionFunction{}
+}
+
+// ParsePermissionFunction splits a "resource:action" permission.
+type ParsePermissionFunction struct{}
+
+func (f *ParsePermissionFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_permission"
+}
+
+func (f *ParsePermissionFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Split a permission into its resource and action",
+		MarkdownDescription: "Returns an object with the `resource` and `action` of a permission in the `resource:action` format.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "permission",
+				MarkdownDescription: "The permission, for example `document:read`",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: permissionAttributeTypes,
+		},
+	}
+}
+
+func (f *ParsePermissionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var permission string
+
+	resp.Error = req.Arguments.Get(ctx, &permission)
+	if resp.Error != nil {
+		return
+	}
+
+	resource, action, found := strings.Cut(permission, ":")
+	if !found || resource == "" || action == "" || strings.Contains(action, ":") {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid permission %q, expected the format resource:action", permission))
+		return
+	}
+
+	result, diags := types.ObjectValue(permissionAttributeTypes, map[string]attr.Value{
+		"resource": types.StringValue(resource),
+		"action":   types.StringValue(action),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
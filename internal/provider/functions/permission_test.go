@@ -0,0 +1,68 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runFunction calls f with arguments, the way Terraform does, and returns its
+// result.
+func runFunction(f function.Function, result attr.Value, arguments ...attr.Value) (attr.Value, *function.FuncError) {
+	resp := &function.RunResponse{Result: function.NewResultData(result)}
+	f.Run(context.Background(), function.RunRequest{Arguments: function.NewArgumentsData(arguments)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func TestPermissionFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		action   string
+		want     string
+		wantErr  bool
+	}{
+		{"valid", "document", "read", "document:read", false},
+		{"empty resource", "", "read", "", true},
+		{"empty action", "document", "", "", true},
+		{"separator in resource", "doc:ument", "read", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runFunction(NewPermissionFunction(), types.StringUnknown(), types.StringValue(tt.resource), types.StringValue(tt.action))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("permission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("permission() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePermissionFunction(t *testing.T) {
+	unknown := types.ObjectUnknown(permissionAttributeTypes)
+
+	got, err := runFunction(NewParsePermissionFunction(), unknown, types.StringValue("document:read"))
+	if err != nil {
+		t.Fatalf("parse_permission() error = %v", err)
+	}
+
+	want := types.ObjectValueMust(permissionAttributeTypes, map[string]attr.Value{
+		"resource": types.StringValue("document"),
+		"action":   types.StringValue("read"),
+	})
+	if !got.Equal(want) {
+		t.Errorf("parse_permission() = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []string{"document", ":read", "document:", "a:b:c"} {
+		if _, err := runFunction(NewParsePermissionFunction(), unknown, types.StringValue(invalid)); err == nil {
+			t.Errorf("parse_permission(%q) should fail", invalid)
+		}
+	}
+}
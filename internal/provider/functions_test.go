@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// TestFunctionDefinitions checks that every provider function has a unique
+// name and a definition the framework accepts.
+func TestFunctionDefinitions(t *testing.T) {
+	ctx := context.Background()
+	names := map[string]bool{}
+
+	for _, newFunction := range permitProvider.(*PermitProvider).Functions(ctx) {
+		f := newFunction()
+
+		var metadata function.MetadataResponse
+		f.Metadata(ctx, function.MetadataRequest{}, &metadata)
+
+		t.Run(metadata.Name, func(t *testing.T) {
+			if names[metadata.Name] {
+				t.Fatalf("duplicate function %s", metadata.Name)
+			}
+			names[metadata.Name] = true
+
+			var definition function.DefinitionResponse
+			f.Definition(ctx, function.DefinitionRequest{}, &definition)
+
+			var validation function.DefinitionValidateResponse
+			definition.Definition.ValidateImplementation(ctx, function.DefinitionValidateRequest{FuncName: metadata.Name}, &validation)
+			for _, d := range validation.Diagnostics.Errors() {
+				t.Errorf("%s: %s", d.Summary(), d.Detail())
+			}
+		})
+	}
+}
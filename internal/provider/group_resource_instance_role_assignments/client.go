@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
 )
 
 type groupResourceInstanceRoleAssignmentClient struct {
@@ -94,7 +96,7 @@ func (c *groupResourceInstanceRoleAssignmentClient) Create(ctx context.Context,
 		return err
 	}
 
-	httpClient := http.DefaultClient
+	httpClient := config.GetHTTPClient()
 
 	apiUrl = strings.TrimSuffix(apiUrl, "/")
 	url := fmt.Sprintf("%s/v2/schema/%s/%s/groups/%s/roles", apiUrl, projectId, envId, plan.Group.ValueString())
@@ -128,6 +130,9 @@ func (c *groupResourceInstanceRoleAssignmentClient) Create(ctx context.Context,
 	}
 	defer resp.Body.Close()
 
+	// The members of the group get the role too
+	common.AssignmentIndexFor(c.client).InvalidateTenant(plan.Tenant.ValueString())
+
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -139,7 +144,7 @@ func (c *groupResourceInstanceRoleAssignmentClient) Create(ctx context.Context,
 	}
 
 	// Generate ID for Terraform state
-	plan.Id = plan.Group
+	plan.Id = plan.assignmentId()
 	return nil
 }
 
@@ -149,7 +154,7 @@ func (c *groupResourceInstanceRoleAssignmentClient) Read(ctx context.Context, da
 		return GroupResourceInstanceRoleAssignmentModel{}, err
 	}
 
-	httpClient := http.DefaultClient
+	httpClient := config.GetHTTPClient()
 
 	apiUrl = strings.TrimSuffix(apiUrl, "/")
 	url := fmt.Sprintf("%s/v2/schema/%s/%s/groups/%s/roles", apiUrl, projectId, envId, data.Group.ValueString())
@@ -212,6 +217,7 @@ func (c *groupResourceInstanceRoleAssignmentClient) Read(ctx context.Context, da
 		return GroupResourceInstanceRoleAssignmentModel{}, fmt.Errorf("group resource instance role assignment not found")
 	}
 
+	data.Id = data.assignmentId()
 	return data, nil
 }
 
@@ -221,7 +227,7 @@ func (c *groupResourceInstanceRoleAssignmentClient) Delete(ctx context.Context,
 		return err
 	}
 
-	httpClient := http.DefaultClient
+	httpClient := config.GetHTTPClient()
 
 	apiUrl = strings.TrimSuffix(apiUrl, "/")
 	url := fmt.Sprintf("%s/v2/schema/%s/%s/groups/%s/roles", apiUrl, projectId, envId, plan.Group.ValueString())
@@ -255,6 +261,9 @@ func (c *groupResourceInstanceRoleAssignmentClient) Delete(ctx context.Context,
 	}
 	defer resp.Body.Close()
 
+	// The members of the group lose the role too
+	common.AssignmentIndexFor(c.client).InvalidateTenant(plan.Tenant.ValueString())
+
 	// Read response body for error details
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
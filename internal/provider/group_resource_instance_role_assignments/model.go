@@ -1,6 +1,9 @@
 package group_resource_instance_role_assignments
 
 import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -11,6 +14,30 @@ type GroupResourceInstanceRoleAssignmentModel struct {
 	Resource         types.String `tfsdk:"resource"`
 	ResourceInstance types.String `tfsdk:"resource_instance"`
 	Tenant           types.String `tfsdk:"tenant"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// groupResourceInstanceRoleAssignmentModelV0 is the state of schema version 0.
+type groupResourceInstanceRoleAssignmentModelV0 struct {
+	Id               types.String `tfsdk:"id"`
+	Group            types.String `tfsdk:"group"`
+	Role             types.String `tfsdk:"role"`
+	Resource         types.String `tfsdk:"resource"`
+	ResourceInstance types.String `tfsdk:"resource_instance"`
+	Tenant           types.String `tfsdk:"tenant"`
+}
+
+// assignmentId returns the ID of the assignment, which uses the import ID
+// format: group:role:resource:resource_instance:tenant.
+func (m GroupResourceInstanceRoleAssignmentModel) assignmentId() types.String {
+	return types.StringValue(strings.Join([]string{
+		m.Group.ValueString(),
+		m.Role.ValueString(),
+		m.Resource.ValueString(),
+		m.ResourceInstance.ValueString(),
+		m.Tenant.ValueString(),
+	}, ":"))
 }
 
 // GroupAddRole represents the API request body.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -11,13 +12,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/config"
 )
 
 var (
-	_ resource.Resource                = &GroupResourceInstanceRoleAssignmentResource{}
-	_ resource.ResourceWithConfigure   = &GroupResourceInstanceRoleAssignmentResource{}
-	_ resource.ResourceWithImportState = &GroupResourceInstanceRoleAssignmentResource{}
+	_ resource.Resource                 = &GroupResourceInstanceRoleAssignmentResource{}
+	_ resource.ResourceWithConfigure    = &GroupResourceInstanceRoleAssignmentResource{}
+	_ resource.ResourceWithImportState  = &GroupResourceInstanceRoleAssignmentResource{}
+	_ resource.ResourceWithUpgradeState = &GroupResourceInstanceRoleAssignmentResource{}
 )
 
 func NewGroupResourceInstanceRoleAssignmentResource() resource.Resource {
@@ -57,14 +60,19 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Metadata(_ context.Context
 }
 
 func (r *GroupResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+	resp.Schema = groupResourceInstanceRoleAssignmentSchema()
+}
+
+func groupResourceInstanceRoleAssignmentSchema() schema.Schema {
+	return schema.Schema{
+		Version: 1,
 		MarkdownDescription: "Assigns a role to a group on a specific resource instance within a tenant. " +
 			"This uses the Permit.io Groups API to manage group-level permissions on resource instances. " +
 			"For user-specific assignments, use `permitio_resource_instance_role_assignment` instead.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Unique identifier of the role assignment",
+				MarkdownDescription: "Identifier of the role assignment, in the format `group:role:resource:resource_instance:tenant`",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -75,6 +83,7 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Schema(_ context.Context,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.FreeFormKeyValidators(),
 			},
 			"role": schema.StringAttribute{
 				Required:            true,
@@ -82,6 +91,7 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Schema(_ context.Context,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"resource": schema.StringAttribute{
 				Required:            true,
@@ -89,6 +99,7 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Schema(_ context.Context,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"resource_instance": schema.StringAttribute{
 				Required:            true,
@@ -96,6 +107,7 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Schema(_ context.Context,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.FreeFormKeyValidators(),
 			},
 			"tenant": schema.StringAttribute{
 				Required:            true,
@@ -103,8 +115,12 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Schema(_ context.Context,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
@@ -115,6 +131,10 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Create(ctx context.Context
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	timeouts := plan.Timeouts
 	if err := r.client.Create(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create group resource instance role assignment",
@@ -123,8 +143,15 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Create(ctx context.Context
 		)
 		return
 	}
+	plan.Timeouts = timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(common.MarkCreated(ctx, resp.Private, time.Now())...)
+
+	common.AwaitWrite(ctx, "group resource instance role assignment", func(ctx context.Context) (bool, error) {
+		_, err := r.client.Read(ctx, plan)
+		return err == nil, err
+	}, &resp.Diagnostics)
 }
 
 func (r *GroupResourceInstanceRoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -134,7 +161,15 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Read(ctx context.Context,
 		return
 	}
 
-	state, err := r.client.Read(ctx, data)
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+
+	var state GroupResourceInstanceRoleAssignmentModel
+	err := common.RetryRecentNotFound(ctx, req.Private, func(ctx context.Context) error {
+		var err error
+		state, err = r.client.Read(ctx, data)
+		return err
+	})
 	if err != nil {
 		// If the resource is not found, remove it from state (drift detection)
 		if strings.Contains(err.Error(), "not found") {
@@ -147,12 +182,23 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Read(ctx context.Context,
 		)
 		return
 	}
+	state.Timeouts = data.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func (r *GroupResourceInstanceRoleAssignmentResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
-	panic("updating GroupResourceInstanceRoleAssignments is not implemented")
+// Update only stores changed timeouts, as every other attribute requires
+// replacement.
+func (r *GroupResourceInstanceRoleAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state GroupResourceInstanceRoleAssignmentModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Timeouts = plan.Timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *GroupResourceInstanceRoleAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -162,6 +208,9 @@ func (r *GroupResourceInstanceRoleAssignmentResource) Delete(ctx context.Context
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
 	if err := r.client.Delete(ctx, &state); err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting group resource instance role assignment",
@@ -189,3 +238,50 @@ func (r *GroupResourceInstanceRoleAssignmentResource) ImportState(ctx context.Co
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_instance"), parts[3])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), parts[4])...)
 }
+
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (r *GroupResourceInstanceRoleAssignmentResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	priorSchema := groupResourceInstanceRoleAssignmentSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		// Version 0 used the group key as the ID, which was shared by every
+		// assignment of the same group.
+		0: {
+			PriorSchema: &priorSchema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior groupResourceInstanceRoleAssignmentModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := GroupResourceInstanceRoleAssignmentModel{
+					Group:            prior.Group,
+					Role:             prior.Role,
+					Resource:         prior.Resource,
+					ResourceInstance: prior.ResourceInstance,
+					Tenant:           prior.Tenant,
+					Timeouts:         common.NullTimeouts(),
+				}
+				state.Id = state.assignmentId()
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			},
+		},
+	}
+}
+
+// groupResourceInstanceRoleAssignmentSchemaV0 is the schema state was written
+// with at version 0. It must not change, whatever is later added to
+// groupResourceInstanceRoleAssignmentSchema.
+func groupResourceInstanceRoleAssignmentSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                schema.StringAttribute{Computed: true},
+			"group":             schema.StringAttribute{Required: true},
+			"role":              schema.StringAttribute{Required: true},
+			"resource":          schema.StringAttribute{Required: true},
+			"resource_instance": schema.StringAttribute{Required: true},
+			"tenant":            schema.StringAttribute{Required: true},
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package group_resource_instance_role_assignments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+// groupAssignmentStateV0 is state written by provider versions that used the
+// group key as the ID.
+const groupAssignmentStateV0 = `{
+	"id": "developers",
+	"group": "developers",
+	"role": "editor",
+	"resource": "workspace",
+	"resource_instance": "ws-123",
+	"tenant": "default"
+}`
+
+func TestUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &GroupResourceInstanceRoleAssignmentResource{}
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("UpgradeState() has no upgrader for version 0")
+	}
+
+	priorState, err := common.DecodeRawState(ctx, *upgrader.PriorSchema, groupAssignmentStateV0)
+	if err != nil {
+		t.Fatalf("DecodeRawState() error = %v", err)
+	}
+
+	request := resource.UpgradeStateRequest{State: priorState}
+	response := resource.UpgradeStateResponse{State: common.NullState(ctx, groupResourceInstanceRoleAssignmentSchema())}
+	upgrader.StateUpgrader(ctx, request, &response)
+
+	if response.Diagnostics.HasError() {
+		t.Fatalf("StateUpgrader() diagnostics = %v", response.Diagnostics)
+	}
+
+	var model GroupResourceInstanceRoleAssignmentModel
+	if diags := response.State.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("State.Get() diagnostics = %v", diags)
+	}
+
+	if want := "developers:editor:workspace:ws-123:default"; model.Id.ValueString() != want {
+		t.Errorf("Id = %v, want %s", model.Id.ValueString(), want)
+	}
+	if model.Group.ValueString() != "developers" || model.Tenant.ValueString() != "default" {
+		t.Errorf("upgraded state = %+v, want the v0 attributes preserved", model)
+	}
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// keyAttribute is the part of resource and data source string attributes this
+// test inspects.
+type keyAttribute interface {
+	IsRequired() bool
+	IsOptional() bool
+	StringValidators() []validator.String
+}
+
+// TestKeyAttributesValidated checks that every configurable key of a resource
+// or data source is validated when planning, instead of only by the API.
+func TestKeyAttributesValidated(t *testing.T) {
+	ctx := context.Background()
+
+	for _, newResource := range permitProvider.Resources(ctx) {
+		r := newResource()
+
+		var metadata resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "permitio"}, &metadata)
+
+		var schemaResponse resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResponse)
+
+		if attribute, ok := schemaResponse.Schema.Attributes["key"].(keyAttribute); ok {
+			checkKeyValidated(t, metadata.TypeName, attribute)
+		}
+	}
+
+	for _, newDataSource := range permitProvider.DataSources(ctx) {
+		d := newDataSource()
+
+		var metadata datasource.MetadataResponse
+		d.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: "permitio"}, &metadata)
+
+		var schemaResponse datasource.SchemaResponse
+		d.Schema(ctx, datasource.SchemaRequest{}, &schemaResponse)
+
+		if attribute, ok := schemaResponse.Schema.Attributes["key"].(keyAttribute); ok {
+			checkKeyValidated(t, metadata.TypeName, attribute)
+		}
+	}
+}
+
+func checkKeyValidated(t *testing.T, typeName string, attribute keyAttribute) {
+	t.Helper()
+
+	if (attribute.IsRequired() || attribute.IsOptional()) && len(attribute.StringValidators()) == 0 {
+		t.Errorf("%s: key has no validators", typeName)
+	}
+}
+
+// setAttribute is the part of resource set attributes this test inspects.
+type setAttribute interface {
+	SetValidators() []validator.Set
+}
+
+// TestAttributeValuesValidated checks that permissions and references to other
+// objects reject malformed values when planning.
+func TestAttributeValuesValidated(t *testing.T) {
+	tests := []struct {
+		typeName  string
+		attribute string
+		value     string
+		valid     bool
+	}{
+		{"permitio_role", "permissions", "document:read", true},
+		{"permitio_role", "permissions", "document:*", true},
+		{"permitio_role", "permissions", "*:read", true},
+		{"permitio_role", "permissions", "read", true},
+		{"permitio_role", "permissions", "*", false},
+		{"permitio_role", "permissions", "document:", false},
+		{"permitio_role", "permissions", "document:read:all", false},
+		{"permitio_role", "permissions", "document read", false},
+		{"permitio_condition_set_rule", "permission", "document:read", true},
+		{"permitio_condition_set_rule", "permission", "document:*", false},
+		{"permitio_condition_set_rule", "permission", "document/read", false},
+		{"permitio_condition_set_rule", "user_set", "user set", false},
+		{"permitio_condition_set_rule", "resource_set", "", false},
+		{"permitio_role", "resource", "__user", true},
+		{"permitio_role", "resource", "document:read", false},
+		{"permitio_relation", "subject_resource", "folder", true},
+		{"permitio_relation", "subject_resource", "folder!", false},
+		{"permitio_relation", "object_resource", "doc ument", false},
+		{"permitio_role_derivation", "on_resource", "folder/1", false},
+		{"permitio_role_derivation", "linked_by", "parent", true},
+		{"permitio_role_derivation", "linked_by", "parent.folder", false},
+		{"permitio_role_assignment", "role", "viewer", true},
+		{"permitio_role_assignment", "role", "view er", false},
+		{"permitio_role_assignment", "tenant", "tenant#1", false},
+	}
+
+	ctx := context.Background()
+	schemas := resourceSchemas(ctx)
+
+	for _, tt := range tests {
+		t.Run(tt.typeName+"."+tt.attribute+"="+tt.value, func(t *testing.T) {
+			attribute, ok := schemas[tt.typeName].Schema.Attributes[tt.attribute]
+			if !ok {
+				t.Fatalf("%s has no attribute %s", tt.typeName, tt.attribute)
+			}
+
+			var diags diag.Diagnostics
+			switch attribute := attribute.(type) {
+			case setAttribute:
+				value := types.SetValueMust(types.StringType, []attr.Value{types.StringValue(tt.value)})
+				for _, v := range attribute.SetValidators() {
+					response := validator.SetResponse{}
+					v.ValidateSet(ctx, validator.SetRequest{Path: path.Root(tt.attribute), ConfigValue: value}, &response)
+					diags.Append(response.Diagnostics...)
+				}
+			case keyAttribute:
+				for _, v := range attribute.StringValidators() {
+					response := validator.StringResponse{}
+					v.ValidateString(ctx, validator.StringRequest{Path: path.Root(tt.attribute), ConfigValue: types.StringValue(tt.value)}, &response)
+					diags.Append(response.Diagnostics...)
+				}
+			default:
+				t.Fatalf("%s.%s is a %T", tt.typeName, tt.attribute, attribute)
+			}
+
+			if valid := !diags.HasError(); valid != tt.valid {
+				t.Errorf("%q valid = %v, want %v: %v", tt.value, valid, tt.valid, diags)
+			}
+		})
+	}
+}
+
+func resourceSchemas(ctx context.Context) map[string]resource.SchemaResponse {
+	schemas := map[string]resource.SchemaResponse{}
+
+	for _, newResource := range permitProvider.Resources(ctx) {
+		r := newResource()
+
+		var metadata resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "permitio"}, &metadata)
+
+		var schemaResponse resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResponse)
+		schemas[metadata.TypeName] = schemaResponse
+	}
+
+	return schemas
+}
@@ -5,17 +5,25 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	permitConfig "github.com/permitio/permit-golang/pkg/config"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/api_keys"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	conditionsetrules "github.com/permitio/terraform-provider-permit-io/internal/provider/conditionset_rules"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/conditionsets"
 	globalconfig "github.com/permitio/terraform-provider-permit-io/internal/provider/config"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/environment_export"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/environment_policy"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/functions"
 	group_resource_instance_role_assignments "github.com/permitio/terraform-provider-permit-io/internal/provider/group_resource_instance_role_assignments"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/proxy_configs"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/relations"
@@ -27,23 +35,36 @@ import (
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/roles"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/tenants"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/user_attributes"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/user_roles"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/users"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 const (
-	DefaultApiUrl  = "https://api.permit.io"
+	DefaultApiUrl  = globalconfig.DefaultApiUrl
 	PDPApiUrl      = "https://localhost:3000"
 	DefaultTimeout = 10 * time.Second
+
+	// DefaultMaxConcurrentRequests matches Terraform's default parallelism.
+	DefaultMaxConcurrentRequests = 10
+	DefaultReadCacheTTL          = 30 * time.Second
+	DefaultWaitForPDPTimeout     = 2 * time.Minute
 )
 
 // Ensure PermitProvider satisfies various provider interfaces.
-var _ provider.Provider = &PermitProvider{}
+var (
+	_ provider.Provider                       = &PermitProvider{}
+	_ provider.ProviderWithFunctions          = &PermitProvider{}
+	_ provider.ProviderWithEphemeralResources = &PermitProvider{}
+)
 
 // PermitProvider defines the provider implementation.
 type PermitProvider struct {
@@ -55,8 +76,24 @@ type PermitProvider struct {
 
 // PermitProviderModel describes the provider data model.
 type PermitProviderModel struct {
-	ApiUrl  types.String `tfsdk:"api_url"`
-	ApiKey  types.String `tfsdk:"api_key"`
+	ApiUrl        types.String `tfsdk:"api_url"`
+	ApiKey        types.String `tfsdk:"api_key"`
+	Timeout       types.Int64  `tfsdk:"timeout"`
+	AdoptExisting types.Bool   `tfsdk:"adopt_existing"`
+
+	MaxConcurrentRequests         types.Int64 `tfsdk:"max_concurrent_requests"`
+	MaxConcurrentSchemaWrites     types.Int64 `tfsdk:"max_concurrent_schema_writes"`
+	MaxConcurrentAssignmentWrites types.Int64 `tfsdk:"max_concurrent_assignment_writes"`
+	ReadCacheTTL                  types.Int64 `tfsdk:"read_cache_ttl"`
+	WaitForConsistency            types.Bool  `tfsdk:"wait_for_consistency"`
+
+	WaitForPDP *WaitForPDPModel `tfsdk:"wait_for_pdp"`
+}
+
+// WaitForPDPModel describes the PDP that resources with wait_for_pdp poll.
+type WaitForPDPModel struct {
+	Url     types.String `tfsdk:"url"`
+	OpaUrl  types.String `tfsdk:"opa_url"`
 	Timeout types.Int64  `tfsdk:"timeout"`
 }
 
@@ -81,7 +118,63 @@ func (p *PermitProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 			},
 			"timeout": schema.Int64Attribute{
 				Optional:            true,
-				MarkdownDescription: "Timeout for the requests to Permit.io API - default is 10 seconds",
+				MarkdownDescription: "Timeout for the requests to Permit.io API - default is 10 seconds. It applies to each request from when it is sent, so time spent waiting for a free slot under `max_concurrent_requests` does not count against it; the `timeouts` block of a resource bounds a whole operation, including retries and polling, and defaults to 20 minutes.",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "When creating an object fails because one with the same key already exists (for example after an interrupted apply), " +
+					"take the existing object into state instead of failing, updating it to match the configuration. " +
+					"Applies to resources, roles, tenants, resource instances, user attributes and relations, and can be overridden per resource - default is false",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "The maximum number of requests to Permit.io API in flight at once, shared by all resources and data sources. " +
+					"Lower it when large environments hit API rate limits. Requests over the limit wait for a slot within the `timeouts` of their resource, not the `timeout` of a request - default is 10",
+				Validators: []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"max_concurrent_schema_writes": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The maximum number of requests creating, updating or deleting schema objects (resources, roles, condition sets, ...) in flight at once, within `max_concurrent_requests` - default is no separate limit",
+				Validators:          []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"max_concurrent_assignment_writes": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The maximum number of requests creating or deleting role assignments and relationship tuples in flight at once, within `max_concurrent_requests` - default is no separate limit",
+				Validators:          []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"read_cache_ttl": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "How long, in seconds, responses of Permit.io API reads are reused by other resources reading the same object during plan and refresh. " +
+					"Concurrent identical reads are always made once, and writes invalidate the objects they touch. 0 disables the cache - default is 30 seconds",
+				Validators: []validator.Int64{int64validator.AtLeast(0)},
+			},
+			"wait_for_consistency": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "After creating or updating an object, poll Permit.io API until it reads back with the configured values, within the `timeouts` of the resource. " +
+					"Enable it when objects written by one resource are used right away by another, or by tests run after the apply - default is false",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for_pdp": schema.SingleNestedBlock{
+				MarkdownDescription: "A PDP that resources setting `wait_for_pdp = true` poll after a write, until it reflects the change. " +
+					"PDPs receive policy updates asynchronously, so use it when checks are made against the PDP right after the apply, for example by integration tests. " +
+					"Supported by role assignments, resource instance role assignments and user roles, which wait until the PDP decides with the change, " +
+					"and by roles, resources, role derivations and condition set rules, which wait until the PDP loads a new policy version.",
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "The URL of the PDP, for example `http://localhost:7766` (Required)",
+					},
+					"opa_url": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "The URL of the OPA inside the PDP, which policy resources read the loaded policy version from - default is port 8181 on the host of `url`",
+					},
+					"timeout": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "How long, in seconds, a write waits for the PDP to reflect it, within the `timeouts` of the resource - default is 120 seconds",
+						Validators:          []validator.Int64{int64validator.AtLeast(1)},
+					},
+				},
 			},
 		},
 	}
@@ -164,6 +257,47 @@ func (p *PermitProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 	}
 
+	var adoptExisting bool
+	adoptExistingStr, adoptExistingExist := os.LookupEnv("PERMITIO_ADOPT_EXISTING")
+	if adoptExistingExist {
+		adoptExistingBool, err := strconv.ParseBool(adoptExistingStr)
+		if err != nil {
+			tflog.Debug(ctx, "Error parsing adopt_existing from env var 'PERMITIO_ADOPT_EXISTING': "+err.Error())
+			resp.Diagnostics.AddAttributeError(
+				path.Root("adopt_existing"),
+				"Adopt existing is not a valid boolean",
+				"The provider cannot create the Permit.io API client as the adopt_existing value is not a valid boolean.",
+			)
+			return
+		}
+		adoptExisting = adoptExistingBool
+	} else {
+		adoptExisting = config.AdoptExisting.ValueBool()
+	}
+
+	maxConcurrentRequests := int64Setting(ctx, "max_concurrent_requests", "PERMITIO_MAX_CONCURRENT_REQUESTS", config.MaxConcurrentRequests, DefaultMaxConcurrentRequests, 1, resp)
+	maxConcurrentSchemaWrites := int64Setting(ctx, "max_concurrent_schema_writes", "PERMITIO_MAX_CONCURRENT_SCHEMA_WRITES", config.MaxConcurrentSchemaWrites, 0, 1, resp)
+	maxConcurrentAssignmentWrites := int64Setting(ctx, "max_concurrent_assignment_writes", "PERMITIO_MAX_CONCURRENT_ASSIGNMENT_WRITES", config.MaxConcurrentAssignmentWrites, 0, 1, resp)
+	readCacheTTL := int64Setting(ctx, "read_cache_ttl", "PERMITIO_READ_CACHE_TTL", config.ReadCacheTTL, int64(DefaultReadCacheTTL/time.Second), 0, resp)
+	waitForConsistency := boolSetting(ctx, "wait_for_consistency", "PERMITIO_WAIT_FOR_CONSISTENCY", config.WaitForConsistency, resp)
+
+	var pdpUrl, pdpOpaUrl string
+	pdpTimeout := DefaultWaitForPDPTimeout
+	if config.WaitForPDP != nil {
+		if config.WaitForPDP.Url.IsNull() || config.WaitForPDP.Url.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("wait_for_pdp").AtName("url"),
+				"Missing PDP URL",
+				"The wait_for_pdp block requires the url of the PDP to wait for.",
+			)
+		}
+		pdpUrl = config.WaitForPDP.Url.ValueString()
+		pdpOpaUrl = config.WaitForPDP.OpaUrl.ValueString()
+		if !config.WaitForPDP.Timeout.IsNull() {
+			pdpTimeout = time.Duration(config.WaitForPDP.Timeout.ValueInt64()) * time.Second
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -174,18 +308,78 @@ func (p *PermitProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "permitio_api_key")
 
 	tflog.Debug(ctx, "Instantiating Permit.io client")
-	clientConfig := permitConfig.NewConfigBuilder(apiKey).WithApiUrl(apiUrl).WithDebug(debug).WithTimeout(time.Duration(timeout)).Build()
+	limitedTransport := common.NewLimitedTransport(http.DefaultTransport, maxConcurrentRequests, map[string]int64{
+		common.RequestFamilySchemaWrites:     maxConcurrentSchemaWrites,
+		common.RequestFamilyAssignmentWrites: maxConcurrentAssignmentWrites,
+	}, time.Duration(timeout))
+	httpClient := &http.Client{
+		Transport: common.NewCachingTransport(limitedTransport, time.Duration(readCacheTTL)*time.Second),
+	}
+	clientConfig := permitConfig.NewConfigBuilder(apiKey).WithApiUrl(apiUrl).WithDebug(debug).WithHTTPClient(httpClient).Build()
 	permitClient := permit.NewPermit(clientConfig)
 
 	// Store config globally for resources that need direct HTTP access
 	globalconfig.SetGlobalConfig(apiUrl, apiKey)
+	globalconfig.SetHTTPClient(httpClient)
+	globalconfig.SetAdoptExisting(adoptExisting)
+	globalconfig.SetWaitForConsistency(waitForConsistency)
+	globalconfig.SetWaitForPDP(pdpUrl, pdpTimeout)
+	globalconfig.SetWaitForPDPOpaUrl(pdpOpaUrl)
 
 	resp.DataSourceData = permitClient
 	resp.ResourceData = permitClient
+	resp.EphemeralResourceData = permitClient
 
 	tflog.Info(ctx, "Permit.io client configured", map[string]any{"success": true})
 }
 
+// int64Setting returns a numeric provider setting, taken from the environment
+// variable when it is set, then from the configuration, then defaultValue.
+func int64Setting(ctx context.Context, attribute string, envVar string, value types.Int64, defaultValue int64, minimum int64, resp *provider.ConfigureResponse) int64 {
+	valueStr, valueExist := os.LookupEnv(envVar)
+	if !valueExist {
+		if value.IsNull() {
+			return defaultValue
+		}
+		return value.ValueInt64()
+	}
+
+	valueInt, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil || valueInt < minimum {
+		tflog.Debug(ctx, "Error parsing "+attribute+" from env var '"+envVar+"'")
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Invalid "+attribute,
+			fmt.Sprintf("The provider cannot create the Permit.io API client as the %s value is not an integer of at least %d.", envVar, minimum),
+		)
+		return defaultValue
+	}
+
+	return valueInt
+}
+
+// boolSetting returns a boolean provider setting, taken from the environment
+// variable when it is set, then from the configuration, then false.
+func boolSetting(ctx context.Context, attribute string, envVar string, value types.Bool, resp *provider.ConfigureResponse) bool {
+	valueStr, valueExist := os.LookupEnv(envVar)
+	if !valueExist {
+		return value.ValueBool()
+	}
+
+	valueBool, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		tflog.Debug(ctx, "Error parsing "+attribute+" from env var '"+envVar+"': "+err.Error())
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Invalid "+attribute,
+			fmt.Sprintf("The provider cannot create the Permit.io API client as the %s value is not a valid boolean.", envVar),
+		)
+		return false
+	}
+
+	return valueBool
+}
+
 func (p *PermitProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		resources.NewResourceResource,
@@ -202,6 +396,8 @@ func (p *PermitProvider) Resources(_ context.Context) []func() resource.Resource
 		resource_instances.NewResourceInstanceResource,
 		resource_instance_role_assignments.NewResourceInstanceRoleAssignmentResource,
 		group_resource_instance_role_assignments.NewGroupResourceInstanceRoleAssignmentResource,
+		user_roles.NewUserRolesResource,
+		environment_policy.NewEnvironmentPolicyResource,
 	}
 }
 
@@ -209,11 +405,30 @@ func (p *PermitProvider) DataSources(_ context.Context) []func() datasource.Data
 	return []func() datasource.DataSource{
 		resources.NewResourceDataSource,
 		roles.NewRoleDataSource,
+		roles.NewRolePermissionsDataSource,
 		conditionsets.NewConditionSetDataSource,
 		users.NewUserDataSource,
+		environment_export.NewEnvironmentExportDataSource,
+	}
+}
+
+func (p *PermitProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		api_keys.NewAPIKeyEphemeralResource,
+		api_keys.NewPDPAPIKeyEphemeralResource,
 	}
 }
 
+func (p *PermitProvider) Functions(_ context.Context) []func() function.Function {
+	return append([]func() function.Function{
+		functions.NewPermissionFunction,
+		functions.NewParsePermissionFunction,
+		functions.NewConditionFunction,
+		functions.NewAllOfFunction,
+		functions.NewAnyOfFunction,
+	}, functions.ImportIDFunctions()...)
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &PermitProvider{
@@ -22,10 +22,7 @@ func (c *proxyConfigClient) create(ctx context.Context, model proxyConfigModel)
 		return proxyConfigModel{}, err
 	}
 
-	resultModel := proxyConfigModel{}
-	resultModel.fromProxyConfigRead(proxyConfig)
-
-	return resultModel, nil
+	return model.result(proxyConfig), nil
 }
 
 func (c *proxyConfigClient) read(ctx context.Context, model proxyConfigModel) (proxyConfigModel, error) {
@@ -35,10 +32,7 @@ func (c *proxyConfigClient) read(ctx context.Context, model proxyConfigModel) (p
 		return proxyConfigModel{}, err
 	}
 
-	resultModel := proxyConfigModel{}
-	resultModel.fromProxyConfigRead(proxyConfig)
-
-	return resultModel, nil
+	return model.result(proxyConfig), nil
 }
 
 func (c *proxyConfigClient) update(ctx context.Context, model proxyConfigModel) (proxyConfigModel, error) {
@@ -54,10 +48,7 @@ func (c *proxyConfigClient) update(ctx context.Context, model proxyConfigModel)
 		return proxyConfigModel{}, err
 	}
 
-	resultModel := proxyConfigModel{}
-	resultModel.fromProxyConfigRead(proxyConfig)
-
-	return resultModel, nil
+	return model.result(proxyConfig), nil
 }
 
 func (c *proxyConfigClient) delete(ctx context.Context, model proxyConfigModel) error {
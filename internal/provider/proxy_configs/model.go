@@ -2,6 +2,7 @@ package proxy_configs
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
@@ -30,10 +31,41 @@ type proxyConfigModel struct {
 	Key            types.String       `tfsdk:"key"`
 	Name           types.String       `tfsdk:"name"`
 	AuthMechanism  types.String       `tfsdk:"auth_mechanism"`
-	AuthSecret     authSecretModel    `tfsdk:"auth_secret"`
+	AuthSecret     *authSecretModel   `tfsdk:"auth_secret"`
+	MappingRules   []mappingRuleModel `tfsdk:"mapping_rules"`
+	Timeouts       timeouts.Value     `tfsdk:"timeouts"`
+
+	// AuthSecretWO is only set from the configuration, as Terraform never
+	// stores write-only values in the plan or state.
+	AuthSecretWO        *authSecretModel `tfsdk:"auth_secret_wo"`
+	AuthSecretWOVersion types.Int64      `tfsdk:"auth_secret_wo_version"`
+}
+
+// proxyConfigModelV0 is the model of version 0 of the schema, from before
+// auth_secret_wo was added.
+type proxyConfigModelV0 struct {
+	Id             types.String       `tfsdk:"id"`
+	OrganizationId types.String       `tfsdk:"organization_id"`
+	ProjectId      types.String       `tfsdk:"project_id"`
+	EnvironmentId  types.String       `tfsdk:"environment_id"`
+	Key            types.String       `tfsdk:"key"`
+	Name           types.String       `tfsdk:"name"`
+	AuthMechanism  types.String       `tfsdk:"auth_mechanism"`
+	AuthSecret     *authSecretModel   `tfsdk:"auth_secret"`
 	MappingRules   []mappingRuleModel `tfsdk:"mapping_rules"`
 }
 
+// secret returns the configured auth secret, whether or not it is write-only.
+func (model *proxyConfigModel) secret() authSecretModel {
+	if model.AuthSecretWO != nil {
+		return *model.AuthSecretWO
+	}
+	if model.AuthSecret != nil {
+		return *model.AuthSecret
+	}
+	return authSecretModel{}
+}
+
 func (model *proxyConfigModel) toProxyConfigCreate(ctx context.Context) (models.ProxyConfigCreate, error) {
 	authMech := models.AuthMechanism(model.AuthMechanism.ValueString())
 	mappingRules := make([]models.MappingRule, len(model.MappingRules))
@@ -84,9 +116,9 @@ func (model *proxyConfigModel) toProxyConfigCreate(ctx context.Context) (models.
 
 	switch models.AuthMechanism(model.AuthMechanism.ValueString()) {
 	case models.BASIC:
-		proxyConfigCreate.Secret = model.AuthSecret.Basic.ValueString()
+		proxyConfigCreate.Secret = model.secret().Basic.ValueString()
 	case models.BEARER:
-		proxyConfigCreate.Secret = model.AuthSecret.Bearer.ValueString()
+		proxyConfigCreate.Secret = model.secret().Bearer.ValueString()
 	case models.HEADERS:
 	}
 
@@ -108,6 +140,26 @@ func (model *proxyConfigModel) toProxyConfigUpdate(ctx context.Context) (models.
 	}, nil
 }
 
+// hasWriteOnlySecret reports whether the secret is set through auth_secret_wo,
+// either in the configuration or, once created, by auth_secret being absent
+// from state.
+func (model *proxyConfigModel) hasWriteOnlySecret() bool {
+	return model.AuthSecret == nil && (model.AuthSecretWO != nil || !model.Id.IsNull())
+}
+
+// result returns the model of a proxy config read from Permit.io, keeping the
+// attributes that only exist in Terraform.
+func (model *proxyConfigModel) result(sdkModel *models.ProxyConfigRead) proxyConfigModel {
+	result := proxyConfigModel{Timeouts: model.Timeouts, AuthSecretWOVersion: model.AuthSecretWOVersion}
+	result.fromProxyConfigRead(sdkModel)
+
+	if model.hasWriteOnlySecret() {
+		result.AuthSecret = nil
+	}
+
+	return result
+}
+
 func (model *proxyConfigModel) fromProxyConfigRead(sdkModel *models.ProxyConfigRead) {
 	model.Id = types.StringValue(sdkModel.Id)
 	model.OrganizationId = types.StringValue(sdkModel.OrganizationId)
@@ -116,6 +168,7 @@ func (model *proxyConfigModel) fromProxyConfigRead(sdkModel *models.ProxyConfigR
 	model.Key = types.StringValue(sdkModel.Key)
 	model.Name = types.StringValue(sdkModel.Name)
 	model.AuthMechanism = types.StringValue(string(*sdkModel.AuthMechanism))
+	model.AuthSecret = &authSecretModel{}
 
 	switch *sdkModel.AuthMechanism {
 	case models.BASIC:
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -13,12 +14,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"strings"
 )
 
 var (
-	_ resource.Resource              = &proxyConfigResource{}
-	_ resource.ResourceWithConfigure = &proxyConfigResource{}
+	_ resource.Resource                 = &proxyConfigResource{}
+	_ resource.ResourceWithConfigure    = &proxyConfigResource{}
+	_ resource.ResourceWithImportState  = &proxyConfigResource{}
+	_ resource.ResourceWithUpgradeState = &proxyConfigResource{}
 )
 
 func NewProxyConfigResource() resource.Resource {
@@ -53,7 +57,12 @@ func (c *proxyConfigResource) Configure(_ context.Context, request resource.Conf
 }
 
 func (c *proxyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+	resp.Schema = proxyConfigSchema()
+}
+
+func proxyConfigSchema() schema.Schema {
+	return schema.Schema{
+		Version:             1,
 		MarkdownDescription: "See [the documentation](https://api.permit.io/v2/redoc#tag/Proxy-Config/operation/create_proxy_config) for more information about proxy configs.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -87,6 +96,7 @@ func (c *proxyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"key": schema.StringAttribute{
 				MarkdownDescription: "Proxy Config is set to enable the Permit Proxy to make proxied requests as part of the Frontend AuthZ.\n\n",
 				Required:            true,
+				Validators:          common.KeyValidators(),
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The name of the proxy config, for example: 'Stripe API",
@@ -100,8 +110,8 @@ func (c *proxyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 			"auth_secret": schema.SingleNestedAttribute{
-				Required:            true,
-				MarkdownDescription: "Proxy config secret is set to enable the Permit Proxy to make proxied requests to the backend service.",
+				Optional:            true,
+				MarkdownDescription: "Proxy config secret is set to enable the Permit Proxy to make proxied requests to the backend service. Exactly one of `auth_secret` and `auth_secret_wo` must be set.",
 				Attributes: map[string]schema.Attribute{
 					"bearer": schema.StringAttribute{
 						Optional: true,
@@ -115,9 +125,33 @@ func (c *proxyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 					},
 				},
 			},
-			"mapping_rules": schema.ListNestedAttribute{
+			"auth_secret_wo": schema.SingleNestedAttribute{
+				Optional:            true,
+				WriteOnly:           true,
+				MarkdownDescription: "Write-only version of `auth_secret`, which is sent to Permit.io but never stored in the plan or state, for example a secret from an ephemeral resource. Change `auth_secret_wo_version` to send a new secret. Requires Terraform 1.11 or later.",
+				Attributes: map[string]schema.Attribute{
+					"bearer": schema.StringAttribute{
+						Optional:  true,
+						WriteOnly: true,
+					},
+					"basic": schema.StringAttribute{
+						Optional:  true,
+						WriteOnly: true,
+					},
+					"headers": schema.MapAttribute{
+						Optional:    true,
+						WriteOnly:   true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+			"auth_secret_wo_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Any number that is changed whenever `auth_secret_wo` is, as Terraform cannot detect changes to write-only values.",
+			},
+			"mapping_rules": schema.SetNestedAttribute{
 				Required:            true,
-				MarkdownDescription: "Proxy config mapping rules will include the rules that will be used to map the request to the backend service by a URL and a http method.",
+				MarkdownDescription: "Proxy config mapping rules will include the rules that will be used to map the request to the backend service by a URL and a http method. Rules are matched by `priority`, so their order in the configuration does not matter.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"url": schema.StringAttribute{
@@ -127,10 +161,12 @@ func (c *proxyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 							Required: true,
 						},
 						"resource": schema.StringAttribute{
-							Required: true,
+							Required:   true,
+							Validators: common.KeyFormatValidators(),
 						},
 						"action": schema.StringAttribute{
-							Optional: true,
+							Optional:   true,
+							Validators: common.KeyFormatValidators(),
 						},
 						"priority": schema.Int64Attribute{
 							Optional: true,
@@ -143,40 +179,67 @@ func (c *proxyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
 func (c *proxyConfigResource) ConfigValidators(context.Context) []resource.ConfigValidator {
 	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("auth_secret"),
+			path.MatchRoot("auth_secret_wo"),
+		),
 		resourcevalidator.Conflicting(
 			path.MatchRoot("auth_secret").AtName("basic"),
 			path.MatchRoot("auth_secret").AtName("bearer"),
 			path.MatchRoot("auth_secret").AtName("headers"),
 		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("auth_secret_wo").AtName("basic"),
+			path.MatchRoot("auth_secret_wo").AtName("bearer"),
+			path.MatchRoot("auth_secret_wo").AtName("headers"),
+		),
 	}
 }
 
+// ValidateConfig checks that the secret of the configured auth_mechanism is set.
+// The secret is read as an object, as values from ephemeral resources are
+// unknown during validation.
 func (c *proxyConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
-	var data proxyConfigModel
+	var (
+		authMechanism types.String
+		authSecret    types.Object
+	)
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	root := "auth_secret"
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auth_mechanism"), &authMechanism)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(root), &authSecret)...)
 
-	if resp.Diagnostics.HasError() {
-		return
+	if !resp.Diagnostics.HasError() && authSecret.IsNull() {
+		root = "auth_secret_wo"
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(root), &authSecret)...)
 	}
 
-	if strings.EqualFold(data.AuthMechanism.ValueString(), string(models.BASIC)) && data.AuthSecret.Basic.IsNull() {
-		resp.Diagnostics.AddError("auth_mechanism was set to `basic` but auth_secret.basic is not set", "")
+	if resp.Diagnostics.HasError() || authMechanism.IsUnknown() || authSecret.IsNull() || authSecret.IsUnknown() {
 		return
 	}
 
-	if strings.EqualFold(data.AuthMechanism.ValueString(), string(models.BEARER)) && data.AuthSecret.Bearer.IsNull() {
-		resp.Diagnostics.AddError("auth_mechanism was set to `bearer` but auth_secret.bearer is not set", "")
-		return
-	}
+	for _, mechanism := range []models.AuthMechanism{models.BASIC, models.BEARER, models.HEADERS} {
+		if !strings.EqualFold(authMechanism.ValueString(), string(mechanism)) {
+			continue
+		}
+
+		name := strings.ToLower(string(mechanism))
+		value, ok := authSecret.Attributes()[name]
+		if ok && value.IsUnknown() {
+			return
+		}
 
-	if strings.EqualFold(data.AuthMechanism.ValueString(), string(models.HEADERS)) && len(data.AuthSecret.Headers) == 0 {
-		resp.Diagnostics.AddError("auth_mechanism was set to `headers` but auth_secret.headers is not set", "")
+		if headers, isMap := value.(types.Map); !ok || value.IsNull() || (isMap && len(headers.Elements()) == 0) {
+			resp.Diagnostics.AddError(fmt.Sprintf("auth_mechanism was set to `%s` but %s.%s is not set", name, root, name), "")
+		}
 		return
 	}
 }
@@ -187,11 +250,15 @@ func (c *proxyConfigResource) Create(ctx context.Context, request resource.Creat
 	)
 
 	response.Diagnostics.Append(request.Plan.Get(ctx, &model)...)
+	response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("auth_secret_wo"), &model.AuthSecretWO)...)
 
 	if response.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
 	created, err := c.client.create(ctx, model)
 
 	if err != nil {
@@ -208,6 +275,8 @@ func (c *proxyConfigResource) Create(ctx context.Context, request resource.Creat
 	if response.Diagnostics.HasError() {
 		return
 	}
+
+	c.awaitWrite(ctx, model, &response.Diagnostics)
 }
 
 func (c *proxyConfigResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -219,6 +288,9 @@ func (c *proxyConfigResource) Read(ctx context.Context, request resource.ReadReq
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
 	read, err := c.client.read(ctx, model)
 
 	if err != nil {
@@ -241,11 +313,15 @@ func (c *proxyConfigResource) Update(ctx context.Context, request resource.Updat
 	var model proxyConfigModel
 
 	response.Diagnostics.Append(request.Plan.Get(ctx, &model)...)
+	response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("auth_secret_wo"), &model.AuthSecretWO)...)
 
 	if response.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Update, &response.Diagnostics)
+	defer cancel()
+
 	proxyConfig, err := c.client.update(ctx, model)
 
 	if err != nil {
@@ -259,6 +335,20 @@ func (c *proxyConfigResource) Update(ctx context.Context, request resource.Updat
 	if response.Diagnostics.HasError() {
 		return
 	}
+
+	c.awaitWrite(ctx, model, &response.Diagnostics)
+}
+
+// awaitWrite waits until the proxy config reads back with the planned name,
+// auth mechanism and mapping rules.
+func (c *proxyConfigResource) awaitWrite(ctx context.Context, plan proxyConfigModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "proxy config", func(ctx context.Context) (bool, error) {
+		read, err := c.client.read(ctx, plan)
+		return err == nil &&
+			read.Name.Equal(plan.Name) &&
+			read.AuthMechanism.Equal(plan.AuthMechanism) &&
+			len(read.MappingRules) == len(plan.MappingRules), err
+	}, diags)
 }
 
 func (c *proxyConfigResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -269,6 +359,9 @@ func (c *proxyConfigResource) Delete(ctx context.Context, request resource.Delet
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
 	err := c.client.delete(ctx, model)
 
 	if err != nil {
@@ -279,3 +372,84 @@ func (c *proxyConfigResource) Delete(ctx context.Context, request resource.Delet
 		return
 	}
 }
+
+// ImportState implements resource.ResourceWithImportState. The import ID is the
+// proxy config key.
+func (c *proxyConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
+
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (c *proxyConfigResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	priorSchema := proxyConfigSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored mapping_rules as a list, so reordering rules in
+		// configuration planned an update.
+		0: {
+			PriorSchema: &priorSchema,
+			StateUpgrader: func(ctx context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+				var prior proxyConfigModelV0
+
+				response.Diagnostics.Append(request.State.Get(ctx, &prior)...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				model := proxyConfigModel{
+					Id:                  prior.Id,
+					OrganizationId:      prior.OrganizationId,
+					ProjectId:           prior.ProjectId,
+					EnvironmentId:       prior.EnvironmentId,
+					Key:                 prior.Key,
+					Name:                prior.Name,
+					AuthMechanism:       prior.AuthMechanism,
+					AuthSecret:          prior.AuthSecret,
+					MappingRules:        prior.MappingRules,
+					Timeouts:            common.NullTimeouts(),
+					AuthSecretWOVersion: types.Int64Null(),
+				}
+
+				response.Diagnostics.Append(response.State.Set(ctx, &model)...)
+			},
+		},
+	}
+}
+
+// proxyConfigSchemaV0 is the schema state was written with at version 0. It
+// must not change, whatever is later added to proxyConfigSchema.
+func proxyConfigSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":              schema.StringAttribute{Computed: true},
+			"organization_id": schema.StringAttribute{Computed: true},
+			"project_id":      schema.StringAttribute{Computed: true},
+			"environment_id":  schema.StringAttribute{Computed: true},
+			"key":             schema.StringAttribute{Required: true},
+			"name":            schema.StringAttribute{Required: true},
+			"auth_mechanism":  schema.StringAttribute{Required: true},
+			"auth_secret": schema.SingleNestedAttribute{
+				Required: true,
+				Attributes: map[string]schema.Attribute{
+					"bearer":  schema.StringAttribute{Optional: true},
+					"basic":   schema.StringAttribute{Optional: true},
+					"headers": schema.MapAttribute{Optional: true, ElementType: types.StringType},
+				},
+			},
+			"mapping_rules": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"url":         schema.StringAttribute{Required: true},
+						"http_method": schema.StringAttribute{Required: true},
+						"resource":    schema.StringAttribute{Required: true},
+						"action":      schema.StringAttribute{Optional: true},
+						"priority":    schema.Int64Attribute{Optional: true},
+						"headers":     schema.MapAttribute{Optional: true, ElementType: types.StringType},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package proxy_configs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+// proxyConfigStateV0 is state written by provider versions that stored
+// mapping_rules as a list.
+const proxyConfigStateV0 = `{
+	"id": "7c1b5c9e2f3a4d0b8e6f1a2b3c4d5e6f",
+	"organization_id": "org",
+	"project_id": "project",
+	"environment_id": "env",
+	"key": "stripe",
+	"name": "Stripe",
+	"auth_mechanism": "Bearer",
+	"auth_secret": {"bearer": "secret", "basic": null, "headers": null},
+	"mapping_rules": [
+		{"url": "https://api.stripe.com/v1/customers", "http_method": "post", "resource": "customer", "action": "create", "priority": 2, "headers": null},
+		{"url": "https://api.stripe.com/v1/customers/{id}", "http_method": "get", "resource": "customer", "action": null, "priority": null, "headers": {"X-Tenant": "acme"}}
+	]
+}`
+
+func TestUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &proxyConfigResource{}
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("UpgradeState() has no upgrader for version 0")
+	}
+
+	priorState, err := common.DecodeRawState(ctx, *upgrader.PriorSchema, proxyConfigStateV0)
+	if err != nil {
+		t.Fatalf("DecodeRawState() error = %v", err)
+	}
+
+	request := resource.UpgradeStateRequest{State: priorState}
+	response := resource.UpgradeStateResponse{State: common.NullState(ctx, proxyConfigSchema())}
+	upgrader.StateUpgrader(ctx, request, &response)
+
+	if response.Diagnostics.HasError() {
+		t.Fatalf("StateUpgrader() diagnostics = %v", response.Diagnostics)
+	}
+
+	var model proxyConfigModel
+	if diags := response.State.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("State.Get() diagnostics = %v", diags)
+	}
+
+	if model.Key.ValueString() != "stripe" {
+		t.Errorf("Key = %v, want stripe", model.Key)
+	}
+	if model.AuthSecret.Bearer.ValueString() != "secret" {
+		t.Errorf("AuthSecret.Bearer = %v, want secret", model.AuthSecret.Bearer)
+	}
+	if len(model.MappingRules) != 2 {
+		t.Fatalf("len(MappingRules) = %d, want 2", len(model.MappingRules))
+	}
+
+	rules := map[string]mappingRuleModel{}
+	for _, rule := range model.MappingRules {
+		rules[rule.HttpMethod.ValueString()] = rule
+	}
+	if rules["post"].Priority.ValueInt64() != 2 || rules["post"].Action.ValueString() != "create" {
+		t.Errorf("post rule = %+v, want priority 2 and action create", rules["post"])
+	}
+	if !rules["get"].Action.IsNull() || !rules["get"].Priority.IsNull() {
+		t.Errorf("get rule = %+v, want null action and priority", rules["get"])
+	}
+	if len(rules["get"].Headers.Elements()) != 1 {
+		t.Errorf("get rule headers = %v, want one header", rules["get"].Headers)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := &proxyConfigResource{}
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"bearer", `{"auth_mechanism": "Bearer", "auth_secret": {"bearer": "secret"}}`, false},
+		{"missing bearer", `{"auth_mechanism": "Bearer", "auth_secret": {"basic": "user:password"}}`, true},
+		{"write-only basic", `{"auth_mechanism": "Basic", "auth_secret_wo": {"basic": "user:password"}}`, false},
+		{"missing write-only basic", `{"auth_mechanism": "Basic", "auth_secret_wo": {"bearer": "secret"}}`, true},
+		{"empty headers", `{"auth_mechanism": "Headers", "auth_secret": {"headers": {}}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := common.DecodeRawState(ctx, proxyConfigSchema(), tt.config)
+			if err != nil {
+				t.Fatalf("DecodeRawState() error = %v", err)
+			}
+
+			response := resource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, &response)
+
+			if response.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("ValidateConfig() diagnostics = %v, wantErr %v", response.Diagnostics, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package relations
 
 import (
 	"context"
+	"fmt"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type relationClient struct {
@@ -11,6 +13,8 @@ type relationClient struct {
 }
 
 func (c *relationClient) Create(ctx context.Context, plan relationModel) (relationModel, error) {
+	defer common.InvalidateRoleGraph(c.client)
+
 	relationCreate := models.RelationCreate{
 		Key:             plan.Key.ValueString(),
 		Name:            plan.Name.ValueString(),
@@ -18,6 +22,12 @@ func (c *relationClient) Create(ctx context.Context, plan relationModel) (relati
 		SubjectResource: plan.SubjectResource.ValueString(),
 	}
 
+	unlock, err := common.LockResource(ctx, plan.ObjectResource.ValueString())
+	if err != nil {
+		return relationModel{}, err
+	}
+	defer unlock()
+
 	createdRelation, err := c.client.Api.ResourceRelations.Create(ctx, plan.ObjectResource.ValueString(), relationCreate)
 
 	if err != nil {
@@ -27,6 +37,24 @@ func (c *relationClient) Create(ctx context.Context, plan relationModel) (relati
 	return tfModelFromSDK(*createdRelation), nil
 }
 
+// Adopt takes over a relation that already exists with the planned key. Since
+// relations cannot be updated, an existing relation that differs from the plan
+// is reported as an error rather than changed.
+func (c *relationClient) Adopt(ctx context.Context, plan relationModel) (relationModel, error) {
+	existing, err := c.Read(ctx, plan.ObjectResource.ValueString(), plan.Key.ValueString())
+
+	if err != nil {
+		return invalidModel, err
+	}
+
+	if !existing.matchesPlan(plan) {
+		return invalidModel, fmt.Errorf("existing relation %s/%s differs from the configuration and relations cannot be updated; delete it or change the configuration to match",
+			plan.ObjectResource.ValueString(), plan.Key.ValueString())
+	}
+
+	return existing, nil
+}
+
 func (c *relationClient) Read(ctx context.Context, objectResourceKey, key string) (relationModel, error) {
 	readRelation, err := c.client.Api.ResourceRelations.Get(ctx, objectResourceKey, key)
 
@@ -38,5 +66,13 @@ func (c *relationClient) Read(ctx context.Context, objectResourceKey, key string
 }
 
 func (c *relationClient) Delete(ctx context.Context, objectResourceKey, key string) error {
+	defer common.InvalidateRoleGraph(c.client)
+
+	unlock, err := common.LockResource(ctx, objectResourceKey)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return c.client.Api.ResourceRelations.Delete(ctx, objectResourceKey, key)
 }
@@ -1,8 +1,10 @@
 package relations
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type relationModel struct {
@@ -20,6 +22,9 @@ type relationModel struct {
 	ObjectResource    types.String `tfsdk:"object_resource"`
 	SubjectResourceId types.String `tfsdk:"subject_resource_id"`
 	ObjectResourceId  types.String `tfsdk:"object_resource_id"`
+
+	AdoptExisting types.Bool     `tfsdk:"adopt_existing"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 var invalidModel = relationModel{}
@@ -42,3 +47,11 @@ func tfModelFromSDK(m models.RelationRead) relationModel {
 
 	return r
 }
+
+// matchesPlan reports whether a relation read from the API is the one described
+// by the plan. Relations cannot be updated, so any difference rules out adoption.
+func (m *relationModel) matchesPlan(plan relationModel) bool {
+	return common.PlanValueMatches(plan.Name, m.Name) &&
+		common.PlanValueMatches(plan.Description, m.Description) &&
+		(plan.SubjectResource.Equal(m.SubjectResource) || plan.SubjectResource.Equal(m.SubjectResourceId))
+}
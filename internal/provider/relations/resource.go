@@ -3,17 +3,21 @@ package relations
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"strings"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &RelationResource{}
-	_ resource.ResourceWithConfigure = &RelationResource{}
+	_ resource.Resource                = &RelationResource{}
+	_ resource.ResourceWithConfigure   = &RelationResource{}
+	_ resource.ResourceWithImportState = &RelationResource{}
 )
 
 func NewRelationResource() resource.Resource {
@@ -42,6 +46,7 @@ func (c *RelationResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["object_resource"] = schema.StringAttribute{
 		Required:            true,
@@ -49,6 +54,7 @@ func (c *RelationResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 
 	attributes["subject_resource_id"] = schema.StringAttribute{
@@ -59,10 +65,14 @@ func (c *RelationResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 		MarkdownDescription: "The object resource ID",
 		Computed:            true,
 	}
+	attributes["adopt_existing"] = common.AdoptExistingAttribute()
 
 	response.Schema = schema.Schema{
 		Attributes:          attributes,
 		MarkdownDescription: "See [the documentation](https://api.permit.io/v2/redoc#tag/Resource-Relations/operation/create_resource_relation) for more information about Relations",
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
@@ -75,8 +85,16 @@ func (c *RelationResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
 	reality, err := c.client.Create(ctx, plan)
 
+	if common.IsConflictErr(err) && common.ShouldAdoptExisting(plan.AdoptExisting) {
+		tflog.Info(ctx, fmt.Sprintf("Relation %s/%s already exists, adopting it", plan.ObjectResource.ValueString(), plan.Key.ValueString()))
+		reality, err = c.client.Adopt(ctx, plan)
+	}
+
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Failed creating relation",
@@ -85,7 +103,18 @@ func (c *RelationResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
+	reality.AdoptExisting = plan.AdoptExisting
+	reality.Timeouts = plan.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, reality)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	common.AwaitWrite(ctx, "relation", func(ctx context.Context) (bool, error) {
+		read, err := c.client.Read(ctx, plan.ObjectResource.ValueString(), plan.Key.ValueString())
+		return err == nil && read.matchesPlan(plan), err
+	}, &response.Diagnostics)
 }
 
 func (c *RelationResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -97,6 +126,9 @@ func (c *RelationResource) Read(ctx context.Context, request resource.ReadReques
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
 	reality, err := c.client.Read(ctx, model.ObjectResourceId.ValueString(), model.Key.ValueString())
 
 	if err != nil {
@@ -107,14 +139,34 @@ func (c *RelationResource) Read(ctx context.Context, request resource.ReadReques
 		return
 	}
 
+	reality.AdoptExisting = model.AdoptExisting
+	reality.Timeouts = model.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, &reality)...)
 }
 
-func (c *RelationResource) Update(_ context.Context, _ resource.UpdateRequest, response *resource.UpdateResponse) {
-	response.Diagnostics.AddError(
-		"Unsupported operation",
-		"resource relations must be replaced, and cannot be updated",
-	)
+// Update only stores changes to settings that are not sent to Permit, such as
+// timeouts and adopt_existing. The relation itself cannot be updated.
+func (c *RelationResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state relationModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Name.Equal(state.Name) || !common.PlanValueMatches(plan.Description, state.Description) {
+		response.Diagnostics.AddError(
+			"Unsupported operation",
+			"resource relations must be replaced, and cannot be updated",
+		)
+		return
+	}
+
+	state.AdoptExisting = plan.AdoptExisting
+	state.Timeouts = plan.Timeouts
+	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
 }
 
 func (c *RelationResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -125,6 +177,9 @@ func (c *RelationResource) Delete(ctx context.Context, request resource.DeleteRe
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
 	err := c.client.Delete(ctx, model.ObjectResource.ValueString(), model.Key.ValueString())
 
 	if err != nil {
@@ -135,3 +190,24 @@ func (c *RelationResource) Delete(ctx context.Context, request resource.DeleteRe
 		return
 	}
 }
+
+// ImportState implements resource.ResourceWithImportState.
+func (c *RelationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Expected format: object_resource:relation_key
+	idParts := strings.Split(req.ID, ":")
+
+	if len(idParts) != 2 || strings.TrimSpace(idParts[0]) == "" || strings.TrimSpace(idParts[1]) == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID Format",
+			fmt.Sprintf("Expected import ID format: 'object_resource:relation_key'. Got: %q\n\n"+
+				"Example: terraform import permitio_relation.parent \"document:parent\"", req.ID),
+		)
+		return
+	}
+
+	objectResource := strings.TrimSpace(idParts[0])
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("object_resource"), objectResource)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("object_resource_id"), objectResource)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), strings.TrimSpace(idParts[1]))...)
+}
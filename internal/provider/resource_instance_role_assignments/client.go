@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type resourceInstanceRoleAssignmentClient struct {
@@ -24,6 +25,7 @@ func (c *resourceInstanceRoleAssignmentClient) Create(ctx context.Context, plan
 	if err != nil {
 		return err
 	}
+	common.AssignmentIndexFor(c.client).Invalidate(plan.Tenant.ValueString(), plan.User.ValueString())
 	*plan = tfModelFromRoleAssignmentRead(*assignment)
 	return nil
 }
@@ -31,27 +33,20 @@ func (c *resourceInstanceRoleAssignmentClient) Create(ctx context.Context, plan
 func (c *resourceInstanceRoleAssignmentClient) Read(ctx context.Context, data ResourceInstanceRoleAssignmentModel) (ResourceInstanceRoleAssignmentModel, error) {
 	resourceInstance := fmt.Sprintf("%s:%s", data.Resource.ValueString(), data.ResourceInstance.ValueString())
 
-	assignments, err := c.client.Api.RoleAssignments.List(
-		ctx,
-		1, 100, // page, perPage
-		data.User.ValueString(),
-		data.Role.ValueString(),
-		data.Tenant.ValueString(),
-	)
+	assignment, found, err := common.AssignmentIndexFor(c.client).Lookup(ctx, common.RoleAssignmentKey{
+		User:             data.User.ValueString(),
+		Role:             data.Role.ValueString(),
+		Tenant:           data.Tenant.ValueString(),
+		ResourceInstance: resourceInstance,
+	})
 	if err != nil {
 		return ResourceInstanceRoleAssignmentModel{}, err
 	}
-	if assignments == nil {
+	if !found {
 		return ResourceInstanceRoleAssignmentModel{}, fmt.Errorf("resource instance role assignment not found")
 	}
 
-	for _, a := range *assignments {
-		if a.ResourceInstance != nil && *a.ResourceInstance == resourceInstance {
-			return tfModelFromRoleAssignmentRead(a), nil
-		}
-	}
-
-	return ResourceInstanceRoleAssignmentModel{}, fmt.Errorf("resource instance role assignment not found")
+	return tfModelFromRoleAssignmentRead(assignment), nil
 }
 
 func (c *resourceInstanceRoleAssignmentClient) Delete(ctx context.Context, plan *ResourceInstanceRoleAssignmentModel) error {
@@ -64,5 +59,6 @@ func (c *resourceInstanceRoleAssignmentClient) Delete(ctx context.Context, plan
 		plan.Tenant.ValueString(),
 		resourceInstance,
 	)
+	common.AssignmentIndexFor(c.client).Invalidate(plan.Tenant.ValueString(), plan.User.ValueString())
 	return err
 }
@@ -3,6 +3,7 @@ package resource_instance_role_assignments
 import (
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 )
@@ -18,6 +19,9 @@ type ResourceInstanceRoleAssignmentModel struct {
 	Resource         types.String `tfsdk:"resource"`
 	ResourceInstance types.String `tfsdk:"resource_instance"`
 	CreatedAt        types.String `tfsdk:"created_at"`
+
+	WaitForPDP types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 func tfModelFromRoleAssignmentRead(assignment models.RoleAssignmentRead) ResourceInstanceRoleAssignmentModel {
@@ -3,13 +3,16 @@ package resource_instance_role_assignments
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"strings"
+	"time"
 )
 
 var (
@@ -82,6 +85,7 @@ func (r *ResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ res
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.FreeFormKeyValidators(),
 			},
 			"role": schema.StringAttribute{
 				Required:            true,
@@ -89,6 +93,7 @@ func (r *ResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ res
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"tenant": schema.StringAttribute{
 				Required:            true,
@@ -96,6 +101,7 @@ func (r *ResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ res
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"resource": schema.StringAttribute{
 				Required:            true,
@@ -103,6 +109,7 @@ func (r *ResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ res
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"resource_instance": schema.StringAttribute{
 				Required:            true,
@@ -110,6 +117,7 @@ func (r *ResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ res
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.FreeFormKeyValidators(),
 			},
 			"created_at": schema.StringAttribute{
 				Computed: true,
@@ -117,6 +125,10 @@ func (r *ResourceInstanceRoleAssignmentResource) Schema(_ context.Context, _ res
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"wait_for_pdp": common.WaitForPDPAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
 		},
 	}
 }
@@ -128,6 +140,10 @@ func (r *ResourceInstanceRoleAssignmentResource) Create(ctx context.Context, req
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	waitForPDP, timeouts := plan.WaitForPDP, plan.Timeouts
 	if err := r.client.Create(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create resource instance role assignment",
@@ -136,8 +152,16 @@ func (r *ResourceInstanceRoleAssignmentResource) Create(ctx context.Context, req
 		)
 		return
 	}
+	plan.WaitForPDP, plan.Timeouts = waitForPDP, timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(common.MarkCreated(ctx, resp.Private, time.Now())...)
+
+	common.AwaitWrite(ctx, "resource instance role assignment", func(ctx context.Context) (bool, error) {
+		_, err := r.client.Read(ctx, plan)
+		return err == nil, err
+	}, &resp.Diagnostics)
+	r.awaitPDP(ctx, plan, true, &resp.Diagnostics)
 }
 
 func (r *ResourceInstanceRoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -147,7 +171,15 @@ func (r *ResourceInstanceRoleAssignmentResource) Read(ctx context.Context, req r
 		return
 	}
 
-	state, err := r.client.Read(ctx, data)
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+
+	var state ResourceInstanceRoleAssignmentModel
+	err := common.RetryRecentNotFound(ctx, req.Private, func(ctx context.Context) error {
+		var err error
+		state, err = r.client.Read(ctx, data)
+		return err
+	})
 	if err != nil {
 		// If the resource is not found, remove it from state (drift detection)
 		if strings.Contains(err.Error(), "not found") {
@@ -160,12 +192,25 @@ func (r *ResourceInstanceRoleAssignmentResource) Read(ctx context.Context, req r
 		)
 		return
 	}
+	state.WaitForPDP = data.WaitForPDP
+	state.Timeouts = data.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func (r *ResourceInstanceRoleAssignmentResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
-	panic("updating ResourceInstanceRoleAssignments is not implemented")
+// Update only stores changed wait_for_pdp and timeouts, as every other
+// attribute requires replacement.
+func (r *ResourceInstanceRoleAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state ResourceInstanceRoleAssignmentModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.WaitForPDP = plan.WaitForPDP
+	state.Timeouts = plan.Timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *ResourceInstanceRoleAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -175,13 +220,29 @@ func (r *ResourceInstanceRoleAssignmentResource) Delete(ctx context.Context, req
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
 	if err := r.client.Delete(ctx, &state); err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting resource instance role assignment",
 			fmt.Sprintf("Could not unassign role %s from user %s on resource %s instance %s in tenant %s: %s",
 				state.Role.ValueString(), state.User.ValueString(), state.Resource.ValueString(), state.ResourceInstance.ValueString(), state.Tenant.ValueString(), err.Error()),
 		)
+		return
 	}
+
+	r.awaitPDP(ctx, state, false, &resp.Diagnostics)
+}
+
+// awaitPDP waits until the PDP grants the role on the instance to the user
+// when assigned is true, or stops granting it when assigned is false.
+func (r *ResourceInstanceRoleAssignmentResource) awaitPDP(ctx context.Context, model ResourceInstanceRoleAssignmentModel, assigned bool, diags *diag.Diagnostics) {
+	instance := model.Resource.ValueString() + ":" + model.ResourceInstance.ValueString()
+	common.AwaitPDP(ctx, "resource instance role assignment", model.WaitForPDP, func(ctx context.Context, pdp *common.PDPClient) (bool, error) {
+		hasRole, err := pdp.HasRole(ctx, model.User.ValueString(), model.Tenant.ValueString(), instance, model.Role.ValueString())
+		return hasRole == assigned, err
+	}, diags)
 }
 
 func (r *ResourceInstanceRoleAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
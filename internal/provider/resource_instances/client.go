@@ -2,10 +2,10 @@ package resource_instances
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type resourceInstanceClient struct {
@@ -13,13 +13,9 @@ type resourceInstanceClient struct {
 }
 
 func (c *resourceInstanceClient) Create(ctx context.Context, plan resourceInstanceModel) (resourceInstanceModel, error) {
-	// Parse attributes from JSON string
-	var attributes map[string]interface{}
-	if !plan.Attributes.IsNull() && plan.Attributes.ValueString() != "" {
-		err := json.Unmarshal([]byte(plan.Attributes.ValueString()), &attributes)
-		if err != nil {
-			return resourceInstanceModel{}, err
-		}
+	attributes, err := common.AttributesFromDynamic(ctx, plan.Attributes)
+	if err != nil {
+		return resourceInstanceModel{}, err
 	}
 
 	instanceCreate := models.NewResourceInstanceCreate(
@@ -43,7 +39,28 @@ func (c *resourceInstanceClient) Create(ctx context.Context, plan resourceInstan
 		return resourceInstanceModel{}, fmt.Errorf("create returned nil response")
 	}
 
-	return tfModelFromResourceInstanceRead(*created), nil
+	return tfModelFromResourceInstanceRead(*created)
+}
+
+// Adopt takes over a resource instance that already exists with the planned
+// key, updating its attributes when they differ from the plan. The tenant
+// cannot be changed in place, so an instance in another tenant is an error.
+func (c *resourceInstanceClient) Adopt(ctx context.Context, plan resourceInstanceModel) (resourceInstanceModel, error) {
+	existing, err := c.Read(ctx, plan.Key.ValueString(), plan.Resource.ValueString())
+	if err != nil {
+		return resourceInstanceModel{}, err
+	}
+
+	if !existing.Tenant.Equal(plan.Tenant) {
+		return resourceInstanceModel{}, fmt.Errorf("existing instance %s:%s belongs to tenant %q, not %q, and cannot be adopted",
+			plan.Resource.ValueString(), plan.Key.ValueString(), existing.Tenant.ValueString(), plan.Tenant.ValueString())
+	}
+
+	if existing.matchesPlan(ctx, plan) {
+		return existing, nil
+	}
+
+	return c.Update(ctx, plan, resourceInstanceModel{})
 }
 
 func (c *resourceInstanceClient) Read(ctx context.Context, key string, resource string) (resourceInstanceModel, error) {
@@ -56,17 +73,17 @@ func (c *resourceInstanceClient) Read(ctx context.Context, key string, resource
 		return resourceInstanceModel{}, fmt.Errorf("instance %s not found", instanceId)
 	}
 
-	return tfModelFromResourceInstanceRead(*instance), nil
+	return tfModelFromResourceInstanceRead(*instance)
 }
 
-func (c *resourceInstanceClient) Update(ctx context.Context, plan resourceInstanceModel) (resourceInstanceModel, error) {
-	// Parse attributes from JSON string
-	var attributes map[string]interface{}
-	if !plan.Attributes.IsNull() && plan.Attributes.ValueString() != "" {
-		err := json.Unmarshal([]byte(plan.Attributes.ValueString()), &attributes)
-		if err != nil {
-			return resourceInstanceModel{}, err
-		}
+// Update writes the planned attributes to the instance. prior is the state
+// before the update; in merge mode the attributes it managed that are no
+// longer planned are removed, and every other key set outside of Terraform is
+// kept.
+func (c *resourceInstanceClient) Update(ctx context.Context, plan resourceInstanceModel, prior resourceInstanceModel) (resourceInstanceModel, error) {
+	attributes, err := c.attributesToWrite(ctx, plan, prior)
+	if err != nil {
+		return resourceInstanceModel{}, err
 	}
 
 	instanceUpdate := models.NewResourceInstanceUpdate()
@@ -83,7 +100,35 @@ func (c *resourceInstanceClient) Update(ctx context.Context, plan resourceInstan
 		return resourceInstanceModel{}, fmt.Errorf("update returned nil response for %s", instanceId)
 	}
 
-	return tfModelFromResourceInstanceRead(*updated), nil
+	return tfModelFromResourceInstanceRead(*updated)
+}
+
+func (c *resourceInstanceClient) attributesToWrite(ctx context.Context, plan resourceInstanceModel, prior resourceInstanceModel) (map[string]any, error) {
+	attributes, err := common.AttributesFromDynamic(ctx, plan.Attributes)
+	if err != nil || !common.IsMergeMode(plan.AttributesMode) {
+		return attributes, err
+	}
+
+	// Keys are only removed when they were managed in merge mode before, so
+	// switching from authoritative mode keeps the unconfigured ones.
+	var priorAttributes map[string]any
+	if common.IsMergeMode(prior.AttributesMode) {
+		priorAttributes, err = common.AttributesFromDynamic(ctx, prior.Attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	instanceId := fmt.Sprintf("%s:%s", plan.Resource.ValueString(), plan.Key.ValueString())
+	current, err := c.client.Api.ResourceInstances.Get(ctx, instanceId)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("instance %s not found", instanceId)
+	}
+
+	return common.MergeAttributes(current.Attributes, priorAttributes, attributes), nil
 }
 
 func (c *resourceInstanceClient) Delete(ctx context.Context, key string, resource string) error {
@@ -1,26 +1,41 @@
 package resource_instances
 
 import (
-	"encoding/json"
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type resourceInstanceModel struct {
-	Id             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	ProjectId      types.String `tfsdk:"project_id"`
-	EnvironmentId  types.String `tfsdk:"environment_id"`
-	CreatedAt      types.String `tfsdk:"created_at"`
-	UpdatedAt      types.String `tfsdk:"updated_at"`
-	Key            types.String `tfsdk:"key"`
-	Resource       types.String `tfsdk:"resource"`
-	ResourceId     types.String `tfsdk:"resource_id"`
-	Tenant         types.String `tfsdk:"tenant"`
-	Attributes     types.String `tfsdk:"attributes"`
+	Id             types.String  `tfsdk:"id"`
+	OrganizationId types.String  `tfsdk:"organization_id"`
+	ProjectId      types.String  `tfsdk:"project_id"`
+	EnvironmentId  types.String  `tfsdk:"environment_id"`
+	CreatedAt      types.String  `tfsdk:"created_at"`
+	UpdatedAt      types.String  `tfsdk:"updated_at"`
+	Key            types.String  `tfsdk:"key"`
+	Resource       types.String  `tfsdk:"resource"`
+	ResourceId     types.String  `tfsdk:"resource_id"`
+	Tenant         types.String  `tfsdk:"tenant"`
+	Attributes     types.Dynamic `tfsdk:"attributes"`
+	AttributesMode types.String  `tfsdk:"attributes_mode"`
+	AdoptExisting  types.Bool    `tfsdk:"adopt_existing"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// matchesPlan reports whether a resource instance read from the API already has
+// the attributes set in the plan, so adopting it needs no update. In merge mode
+// only the planned attribute keys are compared.
+func (m *resourceInstanceModel) matchesPlan(ctx context.Context, plan resourceInstanceModel) bool {
+	attributes := common.AttributesForState(ctx, plan.AttributesMode, plan.Attributes, m.Attributes)
+
+	return plan.Attributes.IsUnknown() || common.DynamicAttributesEqual(ctx, plan.Attributes, attributes)
 }
 
-func tfModelFromResourceInstanceRead(m models.ResourceInstanceRead) resourceInstanceModel {
+func tfModelFromResourceInstanceRead(m models.ResourceInstanceRead) (resourceInstanceModel, error) {
 	r := resourceInstanceModel{}
 	r.Id = types.StringValue(m.Id)
 	r.Key = types.StringValue(m.Key)
@@ -33,17 +48,11 @@ func tfModelFromResourceInstanceRead(m models.ResourceInstanceRead) resourceInst
 	r.UpdatedAt = types.StringValue(m.UpdatedAt.String())
 	r.Tenant = types.StringPointerValue(m.Tenant)
 
-	// Convert attributes map to JSON string
-	if len(m.Attributes) > 0 {
-		attributesJSON, err := json.Marshal(m.Attributes)
-		if err == nil {
-			r.Attributes = types.StringValue(string(attributesJSON))
-		} else {
-			r.Attributes = types.StringValue("{}")
-		}
-	} else {
-		r.Attributes = types.StringNull()
+	attributes, err := common.AttributesToDynamic(m.Attributes)
+	if err != nil {
+		return resourceInstanceModel{}, err
 	}
+	r.Attributes = attributes
 
-	return r
+	return r, nil
 }
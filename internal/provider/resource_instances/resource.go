@@ -3,20 +3,27 @@ package resource_instances
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"strings"
+	"time"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &ResourceInstanceResource{}
-	_ resource.ResourceWithConfigure   = &ResourceInstanceResource{}
-	_ resource.ResourceWithImportState = &ResourceInstanceResource{}
+	_ resource.Resource                 = &ResourceInstanceResource{}
+	_ resource.ResourceWithConfigure    = &ResourceInstanceResource{}
+	_ resource.ResourceWithUpgradeState = &ResourceInstanceResource{}
+	_ resource.ResourceWithImportState  = &ResourceInstanceResource{}
+	_ resource.ResourceWithModifyPlan   = &ResourceInstanceResource{}
 )
 
 func NewResourceInstanceResource() resource.Resource {
@@ -41,12 +48,19 @@ func (r *ResourceInstanceResource) Schema(_ context.Context, _ resource.SchemaRe
 	delete(attributes, "name")
 	delete(attributes, "description")
 
+	// Instance keys are often IDs of other systems, so only their length is
+	// checked.
+	key := attributes["key"].(schema.StringAttribute)
+	key.Validators = common.FreeFormKeyValidators()
+	attributes["key"] = key
+
 	attributes["resource"] = schema.StringAttribute{
 		MarkdownDescription: "The resource type key that this instance belongs to.",
 		Required:            true,
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["resource_id"] = schema.StringAttribute{
 		MarkdownDescription: "The unique resource type ID.",
@@ -61,17 +75,47 @@ func (r *ResourceInstanceResource) Schema(_ context.Context, _ resource.SchemaRe
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
-	attributes["attributes"] = schema.StringAttribute{
-		MarkdownDescription: "Arbitrary resource instance attributes in JSON format that will be used to enforce attribute-based access control policies.",
+	attributes["attributes"] = schema.DynamicAttribute{
+		MarkdownDescription: "Arbitrary resource instance attributes that will be used to enforce attribute-based access control policies, as an object such as `{ owner = \"alice\", size = 3 }`. A JSON-encoded string is still accepted but deprecated. Every key must be declared in the `attributes` of the instance's `permitio_resource` and hold a value of its declared type; mismatches are reported as warnings when planning, as the schema may change in the same apply.",
 		Optional:            true,
 		Computed:            true,
+		Validators: []validator.Dynamic{
+			common.AttributesValidator(),
+		},
 	}
+	attributes["attributes_mode"] = common.AttributesModeAttribute()
+	attributes["adopt_existing"] = common.AdoptExistingAttribute()
 
 	resp.Schema = schema.Schema{
+		Version:             1,
 		Attributes:          attributes,
 		MarkdownDescription: "Manages a Permit.io resource instance. Resource instances represent specific objects of a resource type (e.g., a specific document, project, or folder). See [the documentation](https://api.permit.io/v2/redoc#tag/Resource-Instances) for more information.",
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
+	}
+}
+
+// ModifyPlan checks the configured attributes against the attributes declared
+// on the instance's resource.
+func (r *ResourceInstanceResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if r.client.client == nil || request.Plan.Raw.IsNull() {
+		return
 	}
+
+	var resourceKey types.String
+	var attributes types.Dynamic
+
+	response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("resource"), &resourceKey)...)
+	response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("attributes"), &attributes)...)
+
+	if response.Diagnostics.HasError() || resourceKey.IsUnknown() {
+		return
+	}
+
+	common.ValidateAttributesSchema(ctx, r.client.client, resourceKey.ValueString(), attributes, &response.Diagnostics)
 }
 
 func (r *ResourceInstanceResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
@@ -83,8 +127,16 @@ func (r *ResourceInstanceResource) Create(ctx context.Context, request resource.
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
 	instanceRead, err := r.client.Create(ctx, plan)
 
+	if common.IsConflictErr(err) && common.ShouldAdoptExisting(plan.AdoptExisting) {
+		tflog.Info(ctx, fmt.Sprintf("Resource instance %s:%s already exists, adopting it", plan.Resource.ValueString(), plan.Key.ValueString()))
+		instanceRead, err = r.client.Adopt(ctx, plan)
+	}
+
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Unable to create resource instance",
@@ -93,7 +145,14 @@ func (r *ResourceInstanceResource) Create(ctx context.Context, request resource.
 		return
 	}
 
+	instanceRead.Attributes = common.AttributesForState(ctx, plan.AttributesMode, plan.Attributes, instanceRead.Attributes)
+	instanceRead.AttributesMode = plan.AttributesMode
+	instanceRead.AdoptExisting = plan.AdoptExisting
+	instanceRead.Timeouts = plan.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, instanceRead)...)
+	response.Diagnostics.Append(common.MarkCreated(ctx, response.Private, time.Now())...)
+
+	r.awaitWrite(ctx, plan, &response.Diagnostics)
 }
 
 func (r *ResourceInstanceResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -105,7 +164,15 @@ func (r *ResourceInstanceResource) Read(ctx context.Context, request resource.Re
 		return
 	}
 
-	instanceRead, err := r.client.Read(ctx, model.Key.ValueString(), model.Resource.ValueString())
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
+	var instanceRead resourceInstanceModel
+	err := common.RetryRecentNotFound(ctx, request.Private, func(ctx context.Context) error {
+		var err error
+		instanceRead, err = r.client.Read(ctx, model.Key.ValueString(), model.Resource.ValueString())
+		return err
+	})
 
 	if err != nil {
 		if common.IsNotFoundErr(err) {
@@ -119,19 +186,27 @@ func (r *ResourceInstanceResource) Read(ctx context.Context, request resource.Re
 		return
 	}
 
+	instanceRead.AttributesMode = common.AttributesModeFromState(model.AttributesMode)
+	instanceRead.Attributes = common.AttributesForState(ctx, instanceRead.AttributesMode, model.Attributes, instanceRead.Attributes)
+	instanceRead.AdoptExisting = model.AdoptExisting
+	instanceRead.Timeouts = model.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, &instanceRead)...)
 }
 
 func (r *ResourceInstanceResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	var plan resourceInstanceModel
+	var plan, state resourceInstanceModel
 
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
 
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	instanceRead, err := r.client.Update(ctx, plan)
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Update, &response.Diagnostics)
+	defer cancel()
+
+	instanceRead, err := r.client.Update(ctx, plan, state)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -141,7 +216,21 @@ func (r *ResourceInstanceResource) Update(ctx context.Context, request resource.
 		return
 	}
 
+	instanceRead.Attributes = common.AttributesForState(ctx, plan.AttributesMode, plan.Attributes, instanceRead.Attributes)
+	instanceRead.AttributesMode = plan.AttributesMode
+	instanceRead.AdoptExisting = plan.AdoptExisting
+	instanceRead.Timeouts = plan.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, instanceRead)...)
+
+	r.awaitWrite(ctx, plan, &response.Diagnostics)
+}
+
+// awaitWrite waits until the instance reads back with the planned values.
+func (r *ResourceInstanceResource) awaitWrite(ctx context.Context, plan resourceInstanceModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "resource instance", func(ctx context.Context) (bool, error) {
+		read, err := r.client.Read(ctx, plan.Key.ValueString(), plan.Resource.ValueString())
+		return err == nil && read.matchesPlan(ctx, plan), err
+	}, diags)
 }
 
 func (r *ResourceInstanceResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -152,6 +241,9 @@ func (r *ResourceInstanceResource) Delete(ctx context.Context, request resource.
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
 	err := r.client.Delete(ctx, model.Key.ValueString(), model.Resource.ValueString())
 
 	if err != nil {
@@ -180,3 +272,11 @@ func (r *ResourceInstanceResource) ImportState(ctx context.Context, req resource
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), idParts[1])...)
 }
+
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (r *ResourceInstanceResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored attributes as a JSON string.
+		0: common.JSONStringAttributesUpgrader("attributes"),
+	}
+}
@@ -0,0 +1,66 @@
+package resource_instances
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// resourceInstanceStateV0 is state written by provider versions that stored attributes
+// as a JSON string.
+const resourceInstanceStateV0 = `{
+	"id": "5d1e7a2c",
+	"organization_id": "org",
+	"project_id": "project",
+	"environment_id": "env",
+	"created_at": "2024-01-01 00:00:00 +0000 UTC",
+	"updated_at": "2024-01-01 00:00:00 +0000 UTC",
+	"key": "doc-123",
+	"resource": "document",
+	"resource_id": "9f8e7d6c",
+	"tenant": "default",
+	"attributes": null,
+	"adopt_existing": null
+}`
+
+func TestUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &ResourceInstanceResource{}
+
+	var schemaResponse resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResponse)
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("UpgradeState() has no upgrader for version 0")
+	}
+
+	request := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(resourceInstanceStateV0)}}
+	var response resource.UpgradeStateResponse
+	upgrader.StateUpgrader(ctx, request, &response)
+
+	if response.Diagnostics.HasError() {
+		t.Fatalf("StateUpgrader() diagnostics = %v", response.Diagnostics)
+	}
+
+	raw, err := response.DynamicValue.Unmarshal(schemaResponse.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %v", err)
+	}
+
+	var model resourceInstanceModel
+	state := tfsdk.State{Schema: schemaResponse.Schema, Raw: raw}
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("State.Get() diagnostics = %v", diags)
+	}
+
+	if !model.Attributes.IsNull() {
+		t.Errorf("Attributes = %v, want null", model.Attributes)
+	}
+	if model.Key.ValueString() != "doc-123" || model.Tenant.ValueString() != "default" {
+		t.Errorf("upgraded state = %+v, want the v0 attributes preserved", model)
+	}
+}
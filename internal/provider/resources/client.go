@@ -7,6 +7,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"strings"
 )
 
 type ResourceClient struct {
@@ -17,6 +19,8 @@ type ResourceMethods interface {
 	ResourceRead(ctx context.Context, data ResourceModel) (ResourceModel, error)
 	ResourceCreate(ctx context.Context, resourcePlan *ResourceModel) error
 	ResourceUpdate(ctx context.Context, resourcePlan *ResourceModel) error
+	ResourceAdopt(ctx context.Context, resourcePlan *ResourceModel) error
+	ResourceDependents(ctx context.Context, key string) ([]string, error)
 }
 
 func (d *ResourceClient) ResourceRead(ctx context.Context, data ResourceModel) (ResourceModel, error) {
@@ -125,6 +129,13 @@ func (r *ResourceClient) ResourceCreate(ctx context.Context, resourcePlan *Resou
 }
 
 func (r *ResourceClient) ResourceUpdate(ctx context.Context, resourcePlan *ResourceModel) error {
+	// The update sends the full actions and attributes maps.
+	unlock, err := common.LockResource(ctx, resourcePlan.Key.ValueString())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	actions := make(map[string]models.ActionBlockEditable)
 	for actionKey, action := range resourcePlan.Actions {
 		// TODO: Known bug with Go SDK - null description doesn't get updated correctly
@@ -187,3 +198,100 @@ func (r *ResourceClient) ResourceUpdate(ctx context.Context, resourcePlan *Resou
 
 	return nil
 }
+
+// ResourceAdopt takes over a resource that already exists with the planned key,
+// updating it when it differs from the plan.
+func (r *ResourceClient) ResourceAdopt(ctx context.Context, resourcePlan *ResourceModel) error {
+	existing, err := r.ResourceRead(ctx, *resourcePlan)
+	if err != nil {
+		return err
+	}
+
+	if !existing.matchesPlan(*resourcePlan) {
+		return r.ResourceUpdate(ctx, resourcePlan)
+	}
+
+	*resourcePlan = existing
+	return nil
+}
+
+// ResourceDependents lists what Permit removes together with the resource:
+// its roles and their assignments, its relations and its instances.
+func (r *ResourceClient) ResourceDependents(ctx context.Context, key string) ([]string, error) {
+	roles, err := common.ListAllPages(func(page int, perPage int) ([]models.ResourceRoleRead, error) {
+		roles, err := r.client.Api.ResourceRoles.List(ctx, page, perPage, key)
+
+		if err != nil || roles == nil {
+			return nil, err
+		}
+
+		return *roles, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []models.RoleAssignmentRead
+	for _, role := range roles {
+		roleAssignments, err := common.ListAllRoleAssignments(ctx, r.client, "", role.Key, "")
+
+		if err != nil {
+			return nil, err
+		}
+
+		// The role filter matches on key only, so keep the assignments made on
+		// instances of this resource.
+		for _, assignment := range roleAssignments {
+			if assignment.ResourceInstance != nil && strings.HasPrefix(*assignment.ResourceInstance, key+":") {
+				assignments = append(assignments, assignment)
+			}
+		}
+	}
+
+	relations, err := common.ListAllPages(func(page int, perPage int) ([]models.RelationRead, error) {
+		relations, err := r.client.Api.ResourceRelations.List(ctx, page, perPage, key)
+
+		if err != nil || relations == nil {
+			return nil, err
+		}
+
+		return *relations, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := common.ListAllPages(func(page int, perPage int) ([]models.ResourceInstanceRead, error) {
+		instances, err := r.client.Api.ResourceInstances.List(ctx, page, perPage, "", key, "")
+
+		if err != nil || instances == nil {
+			return nil, err
+		}
+
+		return *instances, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []string
+
+	if len(roles) > 0 {
+		dependents = append(dependents, common.Pluralize(len(roles), "role"))
+	}
+
+	dependents = append(dependents, common.DescribeRoleAssignments(assignments))
+
+	if len(relations) > 0 {
+		dependents = append(dependents, common.Pluralize(len(relations), "relation"))
+	}
+
+	if len(instances) > 0 {
+		dependents = append(dependents, common.Pluralize(len(instances), "resource instance"))
+	}
+
+	return dependents, nil
+}
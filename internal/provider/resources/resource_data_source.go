@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -127,6 +128,39 @@ type ResourceModel struct {
 	Attributes     attributesModel         `tfsdk:"attributes"`
 }
 
+// matchesPlan reports whether a resource read from the API already has the
+// name, description, URN, actions and attributes set in the plan, so adopting
+// it needs no update.
+func (m *ResourceModel) matchesPlan(plan ResourceModel) bool {
+	if !common.PlanValueMatches(plan.Name, m.Name) ||
+		!common.PlanValueMatches(plan.Description, m.Description) ||
+		!common.PlanValueMatches(plan.Urn, m.Urn) {
+		return false
+	}
+
+	if len(plan.Actions) != len(m.Actions) {
+		return false
+	}
+	for key, planned := range plan.Actions {
+		existing, ok := m.Actions[key]
+		if !ok || !planned.Name.Equal(existing.Name) || !planned.Description.Equal(existing.Description) {
+			return false
+		}
+	}
+
+	if len(plan.Attributes) != len(m.Attributes) {
+		return false
+	}
+	for key, planned := range plan.Attributes {
+		existing, ok := m.Attributes[key]
+		if !ok || !planned.Type.Equal(existing.Type) || !planned.Description.Equal(existing.Description) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (d *ResourceDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
@@ -170,7 +204,8 @@ func (d *ResourceDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				Computed: true,
 			},
 			"key": schema.StringAttribute{
-				Required: true,
+				Required:   true,
+				Validators: common.KeyFormatValidators(),
 			},
 			"name": schema.StringAttribute{
 				Required: true,
@@ -3,12 +3,19 @@ package resources
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/permit-golang/pkg/permit"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
@@ -16,8 +23,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &ResourceResource{}
-	_ resource.ResourceWithConfigure = &ResourceResource{}
+	_ resource.Resource                = &ResourceResource{}
+	_ resource.ResourceWithConfigure   = &ResourceResource{}
+	_ resource.ResourceWithImportState = &ResourceResource{}
+	_ resource.ResourceWithModifyPlan  = &ResourceResource{}
 )
 
 // NewResourceResource is a helper function to simplify the provider implementation.
@@ -30,6 +39,23 @@ type ResourceResource struct {
 	ResourceClient
 }
 
+// resourceResourceModel adds the attributes only the resource has to
+// ResourceModel, which is shared with the data source.
+type resourceResourceModel struct {
+	ResourceModel
+
+	AdoptExisting      types.Bool     `tfsdk:"adopt_existing"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	WaitForPDP         types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// changesPolicy reports whether updating the resource to plan changes its
+// actions, so the PDP loads a new policy version.
+func (m *ResourceModel) changesPolicy(plan ResourceModel) bool {
+	return !slices.Equal(slices.Sorted(maps.Keys(m.Actions)), slices.Sorted(maps.Keys(plan.Actions)))
+}
+
 func (r *ResourceResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
@@ -98,6 +124,7 @@ func (r *ResourceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 			"key": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "A URL-friendly name of the resource (i.e: slug). You will be able to query later using this key instead of the id (UUID) of the resource.",
+				Validators:          common.KeyValidators(),
 			},
 			"name": schema.StringAttribute{
 				Required:            true,
@@ -135,6 +162,9 @@ func (r *ResourceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					},
 				},
 				Required: true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(common.KeyFormatValidators()...),
+				},
 			},
 			"attributes": schema.MapNestedAttribute{
 				MarkdownDescription: "Attributes that each resource of this type defines, and can be used in your ABAC policies.",
@@ -152,15 +182,56 @@ func (r *ResourceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					},
 				},
 				Optional: true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(common.KeyFormatValidators()...),
+				},
 			},
+			"adopt_existing":      common.AdoptExistingAttribute(),
+			"deletion_protection": common.DeletionProtectionAttribute(),
+			"wait_for_pdp":        common.WaitForPDPAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
 		},
 	}
 }
 
+// ModifyPlan warns about the roles, assignments, relations and instances that
+// Permit removes together with the resource when it is destroyed.
+func (r *ResourceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+
+	action, diags := common.PlannedRemoval(ctx, req)
+	resp.Diagnostics.Append(diags...)
+
+	if action == "" || resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state resourceResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dependents, err := r.ResourceDependents(ctx, state.Key.ValueString())
+
+	if err != nil {
+		common.AddCascadeLookupWarning(&resp.Diagnostics, action, "resource", state.Key.ValueString(), err)
+		return
+	}
+
+	common.AddCascadeWarning(&resp.Diagnostics, action, "resource", state.Key.ValueString(), dependents...)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *ResourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var (
-		resourcePlan ResourceModel
+		resourcePlan resourceResourceModel
 	)
 
 	diags := req.Plan.Get(ctx, &resourcePlan)
@@ -168,7 +239,26 @@ func (r *ResourceResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	if err := r.ResourceCreate(ctx, &resourcePlan); err != nil {
+
+	ctx, cancel := common.OperationContext(ctx, resourcePlan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "resource", resourcePlan.WaitForPDP, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.ResourceCreate(ctx, &resourcePlan.ResourceModel)
+
+	if common.IsConflictErr(err) && common.ShouldAdoptExisting(resourcePlan.AdoptExisting) {
+		tflog.Info(ctx, fmt.Sprintf("Resource %s already exists, adopting it", resourcePlan.Key.ValueString()))
+		err = r.ResourceAdopt(ctx, &resourcePlan.ResourceModel)
+		// The PDP already holds the adopted resource, and loads no new policy
+		// version when it matches the plan.
+		policyWait = nil
+	}
+
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create resource",
 			fmt.Sprintf("Unable to create resource: %s", err),
@@ -182,18 +272,24 @@ func (r *ResourceResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	r.awaitWrite(ctx, resourcePlan.ResourceModel, &resp.Diagnostics)
+	policyWait.Await(ctx, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *ResourceResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
-	var data ResourceModel
+	var data resourceResourceModel
 
 	response.Diagnostics.Append(request.State.Get(ctx, &data)...)
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	state, err := r.ResourceRead(ctx, data)
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
+	read, err := r.ResourceRead(ctx, data.ResourceModel)
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Unable to Read Resource",
@@ -201,6 +297,13 @@ func (r *ResourceResource) Read(ctx context.Context, request resource.ReadReques
 		)
 		return
 	}
+	state := resourceResourceModel{
+		ResourceModel:      read,
+		AdoptExisting:      data.AdoptExisting,
+		DeletionProtection: common.DeletionProtectionFromState(data.DeletionProtection),
+		WaitForPDP:         data.WaitForPDP,
+		Timeouts:           data.Timeouts,
+	}
 
 	// Set state
 	diags := response.State.Set(ctx, &state)
@@ -213,16 +316,27 @@ func (r *ResourceResource) Read(ctx context.Context, request resource.ReadReques
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *ResourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var (
-		resourcePlan ResourceModel
+		resourcePlan  resourceResourceModel
+		resourceState resourceResourceModel
 	)
 	diags := req.Plan.Get(ctx, &resourcePlan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &resourceState)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("update %v", resourcePlan.Actions))
 
-	if err := r.ResourceUpdate(ctx, &resourcePlan); err != nil {
+	ctx, cancel := common.OperationContext(ctx, resourcePlan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+
+	waitForPDP := types.BoolValue(resourcePlan.WaitForPDP.ValueBool() && resourceState.changesPolicy(resourcePlan.ResourceModel))
+	policyWait := common.StartPolicyWait(ctx, "resource", waitForPDP, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.ResourceUpdate(ctx, &resourcePlan.ResourceModel); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update resource",
 			fmt.Sprintf("Unable to update resource: %s", err),
@@ -234,18 +348,44 @@ func (r *ResourceResource) Update(ctx context.Context, req resource.UpdateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	r.awaitWrite(ctx, resourcePlan.ResourceModel, &resp.Diagnostics)
+	policyWait.Await(ctx, &resp.Diagnostics)
+}
+
+// awaitWrite waits until the resource reads back with the written values.
+func (r *ResourceResource) awaitWrite(ctx context.Context, written ResourceModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "resource", func(ctx context.Context) (bool, error) {
+		read, err := r.ResourceRead(ctx, written)
+		return err == nil && read.matchesPlan(written), err
+	}, diags)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *ResourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Retrieve values from state
-	var state ResourceModel
+	var state resourceResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if !common.CheckDeletionProtection(state.DeletionProtection, "resource", state.Key.ValueString(), &resp.Diagnostics) {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "resource", state.WaitForPDP, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting a resource also deletes its roles and relations.
+	defer common.InvalidateRoleGraph(r.client)
+
 	err := r.client.Api.Resources.Delete(ctx, state.Key.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -255,4 +395,11 @@ func (r *ResourceResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	policyWait.Await(ctx, &resp.Diagnostics)
+}
+
+// ImportState implements resource.ResourceWithImportState. The import ID is the
+// resource key.
+func (r *ResourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type roleAssignmentClient struct {
@@ -20,25 +21,24 @@ func (c *roleAssignmentClient) Create(ctx context.Context, plan *RoleAssignmentM
 	if err != nil {
 		return err
 	}
+	common.AssignmentIndexFor(c.client).Invalidate(plan.Tenant.ValueString(), plan.User.ValueString())
 	*plan = tfModelFromRoleAssignmentRead(*assignment)
 	return nil
 }
 
 func (c *roleAssignmentClient) Read(ctx context.Context, data RoleAssignmentModel) (RoleAssignmentModel, error) {
-	assignments, err := c.client.Api.RoleAssignments.List(
-		ctx,
-		1, 1, // page, perPage
-		data.User.ValueString(),
-		data.Role.ValueString(),
-		data.Tenant.ValueString(),
-	)
+	assignment, found, err := common.AssignmentIndexFor(c.client).Lookup(ctx, common.RoleAssignmentKey{
+		User:   data.User.ValueString(),
+		Role:   data.Role.ValueString(),
+		Tenant: data.Tenant.ValueString(),
+	})
 	if err != nil {
 		return RoleAssignmentModel{}, err
 	}
-	if assignments == nil || len(*assignments) == 0 {
+	if !found {
 		return RoleAssignmentModel{}, fmt.Errorf("role assignment not found")
 	}
-	return tfModelFromRoleAssignmentRead((*assignments)[0]), nil
+	return tfModelFromRoleAssignmentRead(assignment), nil
 }
 
 func (c *roleAssignmentClient) Delete(ctx context.Context, plan *RoleAssignmentModel) error {
@@ -48,5 +48,6 @@ func (c *roleAssignmentClient) Delete(ctx context.Context, plan *RoleAssignmentM
 		plan.Role.ValueString(),
 		plan.Tenant.ValueString(),
 	)
+	common.AssignmentIndexFor(c.client).Invalidate(plan.Tenant.ValueString(), plan.User.ValueString())
 	return err
 }
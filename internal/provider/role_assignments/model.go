@@ -1,6 +1,7 @@
 package role_assignments
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 )
@@ -14,6 +15,9 @@ type RoleAssignmentModel struct {
 	Role           types.String `tfsdk:"role"`
 	Tenant         types.String `tfsdk:"tenant"`
 	CreatedAt      types.String `tfsdk:"created_at"`
+
+	WaitForPDP types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 func tfModelFromRoleAssignmentRead(assignment models.RoleAssignmentRead) RoleAssignmentModel {
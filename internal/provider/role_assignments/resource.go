@@ -3,13 +3,16 @@ package role_assignments
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"strings"
+	"time"
 )
 
 var (
@@ -80,6 +83,7 @@ func (r *RoleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequ
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.FreeFormKeyValidators(),
 			},
 			"role": schema.StringAttribute{
 				Required:            true,
@@ -87,6 +91,7 @@ func (r *RoleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequ
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"tenant": schema.StringAttribute{
 				Required:            true,
@@ -94,6 +99,7 @@ func (r *RoleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequ
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: common.KeyFormatValidators(),
 			},
 			"created_at": schema.StringAttribute{
 				Computed: true,
@@ -101,6 +107,10 @@ func (r *RoleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequ
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"wait_for_pdp": common.WaitForPDPAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
 		},
 	}
 }
@@ -112,6 +122,10 @@ func (r *RoleAssignmentResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	waitForPDP, timeouts := plan.WaitForPDP, plan.Timeouts
 	if err := r.client.Create(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create role assignment",
@@ -120,8 +134,16 @@ func (r *RoleAssignmentResource) Create(ctx context.Context, req resource.Create
 		)
 		return
 	}
+	plan.WaitForPDP, plan.Timeouts = waitForPDP, timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(common.MarkCreated(ctx, resp.Private, time.Now())...)
+
+	common.AwaitWrite(ctx, "role assignment", func(ctx context.Context) (bool, error) {
+		_, err := r.client.Read(ctx, plan)
+		return err == nil, err
+	}, &resp.Diagnostics)
+	r.awaitPDP(ctx, plan, true, &resp.Diagnostics)
 }
 
 func (r *RoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -131,7 +153,15 @@ func (r *RoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	state, err := r.client.Read(ctx, data)
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+
+	var state RoleAssignmentModel
+	err := common.RetryRecentNotFound(ctx, req.Private, func(ctx context.Context) error {
+		var err error
+		state, err = r.client.Read(ctx, data)
+		return err
+	})
 	if err != nil {
 		// If the resource is not found, remove it from state (drift detection)
 		if strings.Contains(err.Error(), "not found") {
@@ -144,12 +174,25 @@ func (r *RoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequ
 		)
 		return
 	}
+	state.WaitForPDP = data.WaitForPDP
+	state.Timeouts = data.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func (r *RoleAssignmentResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
-	panic("updating RoleAssignments is not implemented")
+// Update only stores changed wait_for_pdp and timeouts, as every other
+// attribute requires replacement.
+func (r *RoleAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state RoleAssignmentModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.WaitForPDP = plan.WaitForPDP
+	state.Timeouts = plan.Timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *RoleAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -159,13 +202,28 @@ func (r *RoleAssignmentResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
 	if err := r.client.Delete(ctx, &state); err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting role assignment",
 			fmt.Sprintf("Could not unassign role %s from user %s in tenant %s: %s",
 				state.Role.ValueString(), state.User.ValueString(), state.Tenant.ValueString(), err.Error()),
 		)
+		return
 	}
+
+	r.awaitPDP(ctx, state, false, &resp.Diagnostics)
+}
+
+// awaitPDP waits until the PDP grants the role to the user when assigned is
+// true, or stops granting it when assigned is false.
+func (r *RoleAssignmentResource) awaitPDP(ctx context.Context, model RoleAssignmentModel, assigned bool, diags *diag.Diagnostics) {
+	common.AwaitPDP(ctx, "role assignment", model.WaitForPDP, func(ctx context.Context, pdp *common.PDPClient) (bool, error) {
+		hasRole, err := pdp.HasRole(ctx, model.User.ValueString(), model.Tenant.ValueString(), "", model.Role.ValueString())
+		return hasRole == assigned, err
+	}, diags)
 }
 
 func (r *RoleAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
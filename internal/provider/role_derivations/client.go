@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"github.com/samber/lo"
 )
 
@@ -13,12 +14,21 @@ type apiClient struct {
 }
 
 func (c *apiClient) Create(ctx context.Context, plan roleDerivationModel) (roleDerivationModel, error) {
+	defer common.InvalidateRoleGraph(c.client)
+
 	derivedRuleCreate := models.DerivedRoleRuleCreate{
 		Role:             plan.Role.ValueString(),
 		OnResource:       plan.OnResource.ValueString(),
 		LinkedByRelation: plan.LinkedByRelation.ValueString(),
 	}
 
+	// The derivation is stored on the target role.
+	unlock, err := common.LockRole(ctx, plan.Resource.ValueString(), plan.ToRole.ValueString())
+	if err != nil {
+		return roleDerivationModel{}, err
+	}
+	defer unlock()
+
 	createdGrant, err := c.client.Api.ImplicitGrants.Create(
 		ctx,
 		plan.Resource.ValueString(),
@@ -44,7 +54,7 @@ func (c *apiClient) Read(ctx context.Context, plan roleDerivationModel) (roleDer
 	}
 
 	if targetRoleRead.GrantedTo == nil {
-		return roleDerivationModel{}, fmt.Errorf("target role has no role grants")
+		return roleDerivationModel{}, fmt.Errorf("derivation not found, target role has no role grants")
 	}
 
 	derivation, found := lo.Find(targetRoleRead.GrantedTo.UsersWithRole, func(item models.DerivedRoleRuleRead) bool {
@@ -62,12 +72,20 @@ func (c *apiClient) Read(ctx context.Context, plan roleDerivationModel) (roleDer
 }
 
 func (c *apiClient) Delete(ctx context.Context, plan roleDerivationModel) error {
+	defer common.InvalidateRoleGraph(c.client)
+
 	derivedRuleDelete := models.DerivedRoleRuleDelete{
 		Role:             plan.Role.ValueString(),
 		OnResource:       plan.OnResource.ValueString(),
 		LinkedByRelation: plan.LinkedByRelation.ValueString(),
 	}
 
+	unlock, err := common.LockRole(ctx, plan.Resource.ValueString(), plan.ToRole.ValueString())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return c.client.Api.ImplicitGrants.Delete(
 		ctx,
 		plan.Resource.ValueString(),
@@ -1,6 +1,7 @@
 package role_derivations
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
 )
@@ -11,6 +12,16 @@ type roleDerivationModel struct {
 	OnResource       types.String `tfsdk:"on_resource"`
 	ToRole           types.String `tfsdk:"to_role"`
 	LinkedByRelation types.String `tfsdk:"linked_by"`
+
+	WaitForPDP types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// sameRule reports whether two models describe the same derivation rule.
+func (m roleDerivationModel) sameRule(other roleDerivationModel) bool {
+	return m.Resource.Equal(other.Resource) && m.ToRole.Equal(other.ToRole) &&
+		m.OnResource.Equal(other.OnResource) && m.Role.Equal(other.Role) &&
+		m.LinkedByRelation.Equal(other.LinkedByRelation)
 }
 
 func tfModelFromDerivedRoleRuleRead(plan roleDerivationModel, m models.DerivedRoleRuleRead) roleDerivationModel {
@@ -21,6 +32,8 @@ func tfModelFromDerivedRoleRuleRead(plan roleDerivationModel, m models.DerivedRo
 	r.OnResource = types.StringValue(m.OnResource)
 	r.Role = types.StringValue(m.Role)
 	r.LinkedByRelation = types.StringValue(m.LinkedByRelation)
+	r.WaitForPDP = plan.WaitForPDP
+	r.Timeouts = plan.Timeouts
 
 	return r
 }
@@ -3,17 +3,21 @@ package role_derivations
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+	"strings"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &RoleDerivationResource{}
-	_ resource.ResourceWithConfigure = &RoleDerivationResource{}
+	_ resource.Resource                = &RoleDerivationResource{}
+	_ resource.ResourceWithConfigure   = &RoleDerivationResource{}
+	_ resource.ResourceWithImportState = &RoleDerivationResource{}
+	_ resource.ResourceWithModifyPlan  = &RoleDerivationResource{}
 )
 
 func NewRoleDerivationResource() resource.Resource {
@@ -41,6 +45,7 @@ func (r *RoleDerivationResource) Schema(_ context.Context, _ resource.SchemaRequ
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["role"] = schema.StringAttribute{
 		MarkdownDescription: "The role that the user will derive.",
@@ -48,6 +53,7 @@ func (r *RoleDerivationResource) Schema(_ context.Context, _ resource.SchemaRequ
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["on_resource"] = schema.StringAttribute{
 		MarkdownDescription: "The resource that the user will derive the role on.",
@@ -55,6 +61,7 @@ func (r *RoleDerivationResource) Schema(_ context.Context, _ resource.SchemaRequ
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["to_role"] = schema.StringAttribute{
 		MarkdownDescription: "The role that you want to create role derivation for.",
@@ -62,6 +69,7 @@ func (r *RoleDerivationResource) Schema(_ context.Context, _ resource.SchemaRequ
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["linked_by"] = schema.StringAttribute{
 		MarkdownDescription: "The relation that links the resource to the role.",
@@ -69,12 +77,66 @@ func (r *RoleDerivationResource) Schema(_ context.Context, _ resource.SchemaRequ
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
+	attributes["wait_for_pdp"] = common.WaitForPDPAttribute()
 
 	resp.Schema = schema.Schema{
 		Attributes:          attributes,
 		MarkdownDescription: "See [the documentation](https://api.permit.io/v2/redoc#tag/Implicit-Grants/operation/create_implicit_grant) for more information on role derivations.",
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
+	}
+}
+
+// ModifyPlan checks a new derivation against the roles and relations of the
+// environment before it is created.
+func (r *RoleDerivationResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if r.client.client == nil || request.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan roleDerivationModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Resource.IsUnknown() || plan.ToRole.IsUnknown() || plan.OnResource.IsUnknown() ||
+		plan.Role.IsUnknown() || plan.LinkedByRelation.IsUnknown() {
+		return
 	}
+
+	var prior *roleDerivationModel
+
+	if !request.State.Raw.IsNull() {
+		var state roleDerivationModel
+
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+		// Every attribute forces replacement, so an unchanged derivation has
+		// nothing to check.
+		if response.Diagnostics.HasError() || state.sameRule(plan) {
+			return
+		}
+
+		prior = &state
+	}
+
+	graph, err := common.SharedRoleGraph(ctx, r.client.client)
+
+	if err != nil {
+		response.Diagnostics.AddWarning(
+			"Unable to validate role derivation",
+			fmt.Sprintf("Could not read the roles and relations of the environment, so the derivation was not checked: %s", err),
+		)
+		return
+	}
+
+	validateDerivation(graph, plan, prior, &response.Diagnostics)
 }
 
 func (r *RoleDerivationResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
@@ -86,6 +148,14 @@ func (r *RoleDerivationResource) Create(ctx context.Context, request resource.Cr
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "role derivation", plan.WaitForPDP, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	roleRead, err := r.client.Create(ctx, plan)
 
 	if err != nil {
@@ -97,6 +167,16 @@ func (r *RoleDerivationResource) Create(ctx context.Context, request resource.Cr
 	}
 
 	response.Diagnostics.Append(response.State.Set(ctx, roleRead)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	common.AwaitWrite(ctx, "role derivation", func(ctx context.Context) (bool, error) {
+		_, err := r.client.Read(ctx, plan)
+		return err == nil, err
+	}, &response.Diagnostics)
+	policyWait.Await(ctx, &response.Diagnostics)
 }
 
 func (r *RoleDerivationResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -108,6 +188,9 @@ func (r *RoleDerivationResource) Read(ctx context.Context, request resource.Read
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
 	reality, err := r.client.Read(ctx, model)
 
 	if err != nil {
@@ -121,8 +204,21 @@ func (r *RoleDerivationResource) Read(ctx context.Context, request resource.Read
 	response.Diagnostics.Append(response.State.Set(ctx, &reality)...)
 }
 
+// Update only stores changed wait_for_pdp and timeouts, as every other
+// attribute requires replacement.
 func (r *RoleDerivationResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	panic("")
+	var plan, state roleDerivationModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	state.WaitForPDP = plan.WaitForPDP
+	state.Timeouts = plan.Timeouts
+	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
 }
 
 func (r *RoleDerivationResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -133,6 +229,14 @@ func (r *RoleDerivationResource) Delete(ctx context.Context, request resource.De
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "role derivation", model.WaitForPDP, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.Delete(ctx, model)
 
 	if err != nil {
@@ -140,5 +244,38 @@ func (r *RoleDerivationResource) Delete(ctx context.Context, request resource.De
 			"Failed deleting role derivation",
 			fmt.Errorf("unable to delete role derivation: %w", err).Error(),
 		)
+		return
+	}
+
+	policyWait.Await(ctx, &response.Diagnostics)
+}
+
+// ImportState implements resource.ResourceWithImportState.
+func (r *RoleDerivationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Expected format: resource:to_role:on_resource:role:linked_by
+	idParts := strings.Split(req.ID, ":")
+
+	if len(idParts) != 5 {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID Format",
+			fmt.Sprintf("Expected import ID format: 'resource:to_role:on_resource:role:linked_by'. Got %d parts, expected 5.\n\n"+
+				"Example: terraform import permitio_role_derivation.folder_editor \"document:editor:folder:editor:parent\"",
+				len(idParts)),
+		)
+		return
+	}
+
+	attributes := []string{"resource", "to_role", "on_resource", "role", "linked_by"}
+
+	for i, attribute := range attributes {
+		value := strings.TrimSpace(idParts[i])
+		if value == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID Format",
+				fmt.Sprintf("Import ID contains an empty %s. All five parts must be non-empty.", attribute),
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(attribute), value)...)
 	}
 }
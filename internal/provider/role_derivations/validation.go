@@ -0,0 +1,81 @@
+package role_derivations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+// validateDerivation overlays the planned derivation on the graph, replacing
+// prior when it is set. A cycle, or a relation that exists but does not link
+// the two resources, is an error. Roles and relations that do not exist are
+// only warnings, since they are often created in the same apply.
+func validateDerivation(graph common.RoleGraph, plan roleDerivationModel, prior *roleDerivationModel, diags *diag.Diagnostics) {
+	resourceKey := plan.Resource.ValueString()
+	onResource := plan.OnResource.ValueString()
+	relation := plan.LinkedByRelation.ValueString()
+	target := common.RoleNodeId(resourceKey, plan.ToRole.ValueString())
+	source := common.RoleNodeId(onResource, plan.Role.ValueString())
+
+	for _, role := range []struct {
+		attribute string
+		id        string
+	}{{"to_role", target}, {"role", source}} {
+		if _, ok := graph.Roles[role.id]; !ok {
+			diags.AddAttributeWarning(
+				path.Root(role.attribute),
+				"Role not found",
+				fmt.Sprintf("Role %q does not exist yet. This is expected when it is created in the same apply; otherwise the apply will fail.", role.id),
+			)
+		}
+	}
+
+	exists, links := graph.RelationLinks(relation, resourceKey, onResource)
+
+	switch {
+	case !exists:
+		diags.AddAttributeWarning(
+			path.Root("linked_by"),
+			"Relation not found",
+			fmt.Sprintf("Relation %q does not exist yet. This is expected when it is created in the same apply; otherwise the apply will fail.", relation),
+		)
+	case !links:
+		diags.AddAttributeError(
+			path.Root("linked_by"),
+			"Relation does not link the resources",
+			fmt.Sprintf("Relation %q does not link %q and %q, so it cannot derive %q from %q.", relation, onResource, resourceKey, target, source),
+		)
+	}
+
+	node := &common.RoleNode{}
+	if existing, ok := graph.Roles[target]; ok {
+		copied := *existing
+		node = &copied
+	}
+	node.Resource = resourceKey
+	node.Key = plan.ToRole.ValueString()
+
+	var rules []models.DerivedRoleRuleRead
+	for _, rule := range node.GrantedTo {
+		if prior != nil && prior.Resource == plan.Resource && prior.ToRole == plan.ToRole &&
+			rule.Role == prior.Role.ValueString() && rule.OnResource == prior.OnResource.ValueString() &&
+			rule.LinkedByRelation == prior.LinkedByRelation.ValueString() {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	node.GrantedTo = append(rules, models.DerivedRoleRuleRead{Role: plan.Role.ValueString(), OnResource: onResource, LinkedByRelation: relation})
+	graph = graph.WithRole(node)
+
+	if cycle := graph.FindDerivationCycle(target); cycle != nil {
+		diags.AddAttributeError(
+			path.Root("to_role"),
+			"Cyclic role derivation",
+			fmt.Sprintf("Deriving %q from %q would make the role derive from itself: %s.", target, source, strings.Join(cycle, " -> ")),
+		)
+	}
+}
@@ -0,0 +1,74 @@
+package role_derivations
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+func testRoleGraph() common.RoleGraph {
+	graph := common.RoleGraph{
+		Roles: map[string]*common.RoleNode{},
+		Relations: []common.RoleRelation{
+			{ObjectResource: "document", SubjectResource: "folder", Key: "parent"},
+			{ObjectResource: "folder", SubjectResource: "workspace", Key: "workspace"},
+			{ObjectResource: "workspace", SubjectResource: "folder", Key: "folders"},
+		},
+	}
+
+	for _, node := range []*common.RoleNode{
+		{Resource: "workspace", Key: "editor"},
+		{Resource: "folder", Key: "editor", GrantedTo: []models.DerivedRoleRuleRead{
+			{Role: "editor", OnResource: "workspace", LinkedByRelation: "workspace"},
+		}},
+		{Resource: "document", Key: "editor"},
+	} {
+		graph.Roles[node.Id()] = node
+	}
+
+	return graph
+}
+
+func derivation(resource, toRole, onResource, role, linkedBy string) roleDerivationModel {
+	return roleDerivationModel{
+		Resource:         types.StringValue(resource),
+		ToRole:           types.StringValue(toRole),
+		OnResource:       types.StringValue(onResource),
+		Role:             types.StringValue(role),
+		LinkedByRelation: types.StringValue(linkedBy),
+	}
+}
+
+func TestValidateDerivation(t *testing.T) {
+	tests := []struct {
+		name         string
+		plan         roleDerivationModel
+		wantWarnings int
+		wantErrors   int
+	}{
+		{"valid", derivation("document", "editor", "folder", "editor", "parent"), 0, 0},
+		{"missing roles", derivation("document", "owner", "folder", "owner", "parent"), 2, 0},
+		{"missing relation", derivation("document", "editor", "folder", "editor", "container"), 1, 0},
+		{"relation links other resources", derivation("document", "editor", "workspace", "editor", "parent"), 0, 1},
+		{"relation in the other direction", derivation("folder", "editor", "document", "editor", "parent"), 0, 1},
+		{"cycle", derivation("workspace", "editor", "folder", "editor", "folders"), 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+
+			validateDerivation(testRoleGraph(), tt.plan, nil, &diags)
+
+			if got := diags.WarningsCount(); got != tt.wantWarnings {
+				t.Errorf("warnings = %d, want %d: %v", got, tt.wantWarnings, diags)
+			}
+			if got := diags.ErrorsCount(); got != tt.wantErrors {
+				t.Errorf("errors = %d, want %d: %v", got, tt.wantErrors, diags)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/permitio/permit-golang/pkg/permit"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"github.com/samber/lo"
+	"strings"
 )
 
 type roleClient struct {
@@ -13,7 +14,9 @@ type roleClient struct {
 }
 
 func (c *roleClient) Create(ctx context.Context, plan roleModel) (roleModel, error) {
-	permissions, err := common.ConvertElementsToSlice[string](ctx, plan.Permissions.Elements())
+	defer common.InvalidateRoleGraph(c.client)
+
+	permissions, err := c.permissionsToWrite(ctx, plan)
 
 	if err != nil {
 		return roleModel{}, err
@@ -25,6 +28,12 @@ func (c *roleClient) Create(ctx context.Context, plan roleModel) (roleModel, err
 		return roleModel{}, err
 	}
 
+	unlock, err := common.LockRole(ctx, plan.Resource.ValueString(), plan.Key.ValueString())
+	if err != nil {
+		return roleModel{}, err
+	}
+	defer unlock()
+
 	var createdModel roleModel
 	if plan.isResourceRole() {
 		roleCreate := models.ResourceRoleCreate{
@@ -63,6 +72,22 @@ func (c *roleClient) Create(ctx context.Context, plan roleModel) (roleModel, err
 	return createdModel, nil
 }
 
+// Adopt takes over a role that already exists with the planned key, updating it
+// when it differs from the plan.
+func (c *roleClient) Adopt(ctx context.Context, plan roleModel) (roleModel, error) {
+	existing, err := c.Read(ctx, plan.Key.ValueString(), plan.Resource.ValueStringPointer())
+
+	if err != nil {
+		return roleModel{}, err
+	}
+
+	if existing.matchesPlan(plan) {
+		return existing, nil
+	}
+
+	return c.Update(ctx, plan)
+}
+
 func (c *roleClient) Read(ctx context.Context, key string, resourceKey *string) (roleModel, error) {
 	var createdModel roleModel
 
@@ -88,7 +113,9 @@ func (c *roleClient) Read(ctx context.Context, key string, resourceKey *string)
 }
 
 func (c *roleClient) Update(ctx context.Context, plan roleModel) (roleModel, error) {
-	desiredPermissions, err := common.ConvertElementsToSlice[string](ctx, plan.Permissions.Elements())
+	defer common.InvalidateRoleGraph(c.client)
+
+	desiredPermissions, err := c.permissionsToWrite(ctx, plan)
 
 	if err != nil {
 		return roleModel{}, err
@@ -100,6 +127,12 @@ func (c *roleClient) Update(ctx context.Context, plan roleModel) (roleModel, err
 		return roleModel{}, err
 	}
 
+	unlock, err := common.LockRole(ctx, plan.Resource.ValueString(), plan.Key.ValueString())
+	if err != nil {
+		return roleModel{}, err
+	}
+	defer unlock()
+
 	var updatedModel roleModel
 	if plan.isResourceRole() {
 		resourceKey := plan.Resource.ValueString()
@@ -206,9 +239,53 @@ func (c *roleClient) Update(ctx context.Context, plan roleModel) (roleModel, err
 }
 
 func (c *roleClient) Delete(ctx context.Context, key string, resourceKey *string) error {
+	defer common.InvalidateRoleGraph(c.client)
+
+	unlock, err := common.LockRole(ctx, lo.FromPtr(resourceKey), key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if resourceKey != nil {
 		return c.client.Api.ResourceRoles.Delete(ctx, *resourceKey, key)
 	} else {
 		return c.client.Api.Roles.Delete(ctx, key)
 	}
 }
+
+// Dependents lists what Permit removes together with the role: its role
+// assignments and, for resource roles, the derivation rules granting it.
+func (c *roleClient) Dependents(ctx context.Context, state roleModel) ([]string, error) {
+	assignments, err := common.ListAllRoleAssignments(ctx, c.client, "", state.Key.ValueString(), "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The role filter matches on key only, so keep the assignments that belong
+	// to this role rather than to a role with the same key on another resource.
+	assignments = lo.Filter(assignments, func(assignment models.RoleAssignmentRead, _ int) bool {
+		if !state.isResourceRole() {
+			return assignment.ResourceInstance == nil
+		}
+		return assignment.ResourceInstance != nil &&
+			strings.HasPrefix(*assignment.ResourceInstance, state.Resource.ValueString()+":")
+	})
+
+	dependents := []string{common.DescribeRoleAssignments(assignments)}
+
+	if state.isResourceRole() {
+		roleRead, err := c.client.Api.ResourceRoles.Get(ctx, state.Resource.ValueString(), state.Key.ValueString())
+
+		if err != nil {
+			return nil, err
+		}
+
+		if roleRead.GrantedTo != nil && len(roleRead.GrantedTo.UsersWithRole) > 0 {
+			dependents = append(dependents, common.Pluralize(len(roleRead.GrantedTo.UsersWithRole), "role derivation"))
+		}
+	}
+
+	return dependents, nil
+}
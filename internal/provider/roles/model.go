@@ -1,9 +1,13 @@
 package roles
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"github.com/samber/lo"
 )
 
@@ -18,16 +22,58 @@ type roleModel struct {
 	Name           types.String `tfsdk:"name"`
 	Description    types.String `tfsdk:"description"`
 	Permissions    types.Set    `tfsdk:"permissions"`
+	Expanded       types.Set    `tfsdk:"expanded_permissions"`
 	Extends        types.Set    `tfsdk:"extends"`
 
 	ResourceId types.String `tfsdk:"resource_id"`
 	Resource   types.String `tfsdk:"resource"`
 }
 
+// roleResourceModel adds the attributes only the resource has to roleModel,
+// which is shared with the data source.
+type roleResourceModel struct {
+	roleModel
+
+	AdoptExisting      types.Bool     `tfsdk:"adopt_existing"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	WaitForPDP         types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// changesPolicy reports whether updating the role to plan changes what it
+// grants, so the PDP loads a new policy version.
+func (m *roleModel) changesPolicy(plan roleModel) bool {
+	return !plan.Expanded.Equal(m.Expanded) || !plan.Extends.Equal(m.Extends)
+}
+
 func (m *roleModel) isResourceRole() bool {
 	return !m.Resource.IsNull()
 }
 
+// matchesPlan reports whether a role read from the API already has the values
+// set in the plan, so adopting it needs no update.
+func (m *roleModel) matchesPlan(plan roleModel) bool {
+	return common.PlanValueMatches(plan.Name, m.Name) &&
+		common.PlanValueMatches(plan.Description, m.Description) &&
+		common.PlanValueMatches(plan.Expanded, m.Expanded) &&
+		common.PlanValueMatches(plan.Extends, m.Extends)
+}
+
+// keepPermissionPatterns keeps the wildcard patterns written in configuration
+// as the permissions of a role read from the API. What they matched stays in
+// expanded_permissions, so a change there shows up as a diff on the next plan.
+func (m *roleModel) keepPermissionPatterns(ctx context.Context, prior types.Set) {
+	if prior.IsNull() || prior.IsUnknown() {
+		return
+	}
+
+	permissions, err := common.ConvertElementsToSlice[string](ctx, prior.Elements())
+
+	if err == nil && hasPermissionPatterns(permissions) {
+		m.Permissions = prior
+	}
+}
+
 func tfModelFromRoleRead(m models.RoleRead) roleModel {
 	r := roleModel{}
 	r.Id = types.StringValue(m.Id)
@@ -49,6 +95,7 @@ func tfModelFromRoleRead(m models.RoleRead) roleModel {
 	} else {
 		r.Permissions, _ = types.SetValue(types.StringType, []attr.Value{})
 	}
+	r.Expanded = r.Permissions
 
 	// Handle extends - ensure proper typing even for empty sets
 	if len(m.Extends) > 0 {
@@ -84,6 +131,7 @@ func tfModelFromResourceRoleRead(resourceKey string, m models.ResourceRoleRead)
 	} else {
 		r.Permissions, _ = types.SetValue(types.StringType, []attr.Value{})
 	}
+	r.Expanded = r.Permissions
 
 	// Handle extends - ensure proper typing even for empty sets
 	if len(m.Extends) > 0 {
@@ -0,0 +1,151 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+// isPermissionPattern reports whether a permission uses a wildcard and has to
+// be expanded before it is sent to Permit.
+func isPermissionPattern(permission string) bool {
+	resource, action, found := strings.Cut(permission, ":")
+	return found && (resource == common.PermissionWildcard || action == common.PermissionWildcard)
+}
+
+func hasPermissionPatterns(permissions []string) bool {
+	for _, permission := range permissions {
+		if isPermissionPattern(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPermissions replaces the wildcard patterns in permissions with the
+// resource:action pairs they match in actions, which maps resource keys to
+// their action keys. Other permissions are kept as they are. It also returns
+// the resources named by patterns that are not in actions, whose expansion is
+// not known yet.
+func expandPermissions(permissions []string, actions map[string][]string) ([]string, []string) {
+	expanded := map[string]struct{}{}
+	var missing []string
+
+	for _, permission := range permissions {
+		if !isPermissionPattern(permission) {
+			expanded[permission] = struct{}{}
+			continue
+		}
+
+		resourcePattern, actionPattern, _ := strings.Cut(permission, ":")
+
+		if resourcePattern != common.PermissionWildcard {
+			if _, ok := actions[resourcePattern]; !ok {
+				missing = append(missing, resourcePattern)
+				continue
+			}
+		}
+
+		for resource, resourceActions := range actions {
+			if resourcePattern != common.PermissionWildcard && resource != resourcePattern {
+				continue
+			}
+
+			for _, action := range resourceActions {
+				if actionPattern == common.PermissionWildcard || action == actionPattern {
+					expanded[resource+":"+action] = struct{}{}
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(expanded))
+	for permission := range expanded {
+		result = append(result, permission)
+	}
+	sort.Strings(result)
+
+	return result, missing
+}
+
+// resolvePermissions expands the wildcard patterns in permissions against
+// actions, like expandPermissions, but fails instead of leaving out the
+// resources that don't exist, so a pattern is never sent to Permit.
+func resolvePermissions(permissions []string, actions map[string][]string) ([]string, error) {
+	if hasPermissionPatterns(permissions) {
+		var missing []string
+		permissions, missing = expandPermissions(permissions, actions)
+
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("permission patterns name resources that don't exist: %s", strings.Join(missing, ", "))
+		}
+	}
+
+	for _, permission := range permissions {
+		if strings.Contains(permission, common.PermissionWildcard) {
+			return nil, fmt.Errorf("permission %q contains a wildcard that was not expanded", permission)
+		}
+	}
+
+	return permissions, nil
+}
+
+// permissionsToWrite returns the permissions to send to Permit for a planned
+// role. expanded_permissions is unknown at plan time when a pattern names a
+// resource created in the same apply, so the patterns are expanded again
+// against the resources as they are now.
+func (c *roleClient) permissionsToWrite(ctx context.Context, plan roleModel) ([]string, error) {
+	if !plan.Expanded.IsNull() && !plan.Expanded.IsUnknown() {
+		expanded, err := common.ConvertElementsToSlice[string](ctx, plan.Expanded.Elements())
+		if err != nil {
+			return nil, err
+		}
+		return resolvePermissions(expanded, nil)
+	}
+
+	permissions, err := common.ConvertElementsToSlice[string](ctx, plan.Permissions.Elements())
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPermissionPatterns(permissions) {
+		return resolvePermissions(permissions, nil)
+	}
+
+	actions, err := c.ResourceActions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolvePermissions(permissions, actions)
+}
+
+// ResourceActions lists the action keys of every resource in the environment,
+// keyed by resource key. Built-in resources are left out, so that "*:read"
+// only matches resources defined by the user.
+func (c *roleClient) ResourceActions(ctx context.Context) (map[string][]string, error) {
+	resources, err := common.ListResources(ctx, c.client, common.ResourceListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make(map[string][]string, len(resources))
+
+	for _, resource := range resources {
+		actions[resource.Key] = []string{}
+
+		if resource.Actions == nil {
+			continue
+		}
+
+		for action := range *resource.Actions {
+			actions[resource.Key] = append(actions[resource.Key], action)
+		}
+	}
+
+	return actions, nil
+}
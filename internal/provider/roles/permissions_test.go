@@ -0,0 +1,126 @@
+package roles
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIsPermissionPattern(t *testing.T) {
+	tests := []struct {
+		permission string
+		want       bool
+	}{
+		{"document:read", false},
+		{"document:*", true},
+		{"*:read", true},
+		{"*:*", true},
+		{"read", false},
+		{"*", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPermissionPattern(tt.permission); got != tt.want {
+			t.Errorf("isPermissionPattern(%q) = %v, want %v", tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPermissions(t *testing.T) {
+	actions := map[string][]string{
+		"document": {"read", "write", "delete"},
+		"folder":   {"read", "create"},
+		"report":   {},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		want        []string
+		wantMissing []string
+	}{
+		{"literals", []string{"document:read", "folder:create"}, []string{"document:read", "folder:create"}, nil},
+		{"every action", []string{"document:*"}, []string{"document:delete", "document:read", "document:write"}, nil},
+		{"every resource", []string{"*:read"}, []string{"document:read", "folder:read"}, nil},
+		{"everything", []string{"*:*"}, []string{"document:delete", "document:read", "document:write", "folder:create", "folder:read"}, nil},
+		{"overlapping", []string{"document:*", "*:read", "document:read"}, []string{"document:delete", "document:read", "document:write", "folder:read"}, nil},
+		{"resource without actions", []string{"report:*"}, []string{}, nil},
+		{"missing resource", []string{"invoice:*", "document:read"}, []string{"document:read"}, []string{"invoice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := expandPermissions(tt.permissions, actions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPermissions(%q) = %q, want %q", tt.permissions, got, tt.want)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("expandPermissions(%q) missing = %q, want %q", tt.permissions, missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestKeepPermissionPatterns(t *testing.T) {
+	ctx := context.Background()
+	read := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("document:read"), types.StringValue("document:write")})
+
+	t.Run("patterns are kept", func(t *testing.T) {
+		prior := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("document:*")})
+		m := roleModel{Permissions: read, Expanded: read}
+		m.keepPermissionPatterns(ctx, prior)
+
+		if !m.Permissions.Equal(prior) {
+			t.Errorf("Permissions = %v, want %v", m.Permissions, prior)
+		}
+		if !m.Expanded.Equal(read) {
+			t.Errorf("Expanded = %v, want %v", m.Expanded, read)
+		}
+	})
+
+	t.Run("literals come from the API", func(t *testing.T) {
+		prior := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("document:read")})
+		m := roleModel{Permissions: read, Expanded: read}
+		m.keepPermissionPatterns(ctx, prior)
+
+		if !m.Permissions.Equal(read) {
+			t.Errorf("Permissions = %v, want %v", m.Permissions, read)
+		}
+	})
+}
+
+func TestResolvePermissions(t *testing.T) {
+	actions := map[string][]string{
+		"document": {"read", "write"},
+		"invoice":  {"pay"},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		actions     map[string][]string
+		want        []string
+		wantErr     bool
+	}{
+		{"literals", []string{"document:read"}, nil, []string{"document:read"}, false},
+		{"pattern of a new resource", []string{"invoice:*"}, actions, []string{"invoice:pay"}, false},
+		{"pattern of a missing resource", []string{"invoice:*"}, map[string][]string{}, nil, true},
+		{"pattern without actions", []string{"*:read"}, nil, []string{}, false},
+		{"unexpanded wildcard", []string{"document:read", "*"}, actions, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePermissions(tt.permissions, tt.actions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePermissions(%q) error = %v, wantErr %v", tt.permissions, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolvePermissions(%q) = %q, want %q", tt.permissions, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,13 +3,17 @@ package roles
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 	"strings"
 )
@@ -19,6 +23,7 @@ var (
 	_ resource.Resource                = &RoleResource{}
 	_ resource.ResourceWithConfigure   = &RoleResource{}
 	_ resource.ResourceWithImportState = &RoleResource{}
+	_ resource.ResourceWithModifyPlan  = &RoleResource{}
 )
 
 func NewRoleResource() resource.Resource {
@@ -42,12 +47,20 @@ func (r *RoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	attributes := common.CreateBaseResourceSchema()
 	attributes["permissions"] = schema.SetAttribute{
 		ElementType:         types.StringType,
-		MarkdownDescription: "list of action keys that define what actions this resource role is permitted to do",
+		MarkdownDescription: "list of action keys that define what actions this resource role is permitted to do. Wildcard patterns such as `document:*` (every action of `document`) and `*:read` (the `read` action of every resource) are expanded when planning, against the resources and actions that exist in the environment at that time.",
 		Computed:            true,
 		Optional:            true,
 		PlanModifiers: []planmodifier.Set{
 			setplanmodifier.UseStateForUnknown(),
 		},
+		Validators: []validator.Set{
+			setvalidator.ValueStringsAre(common.RolePermissionValidator()),
+		},
+	}
+	attributes["expanded_permissions"] = schema.SetAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: "The permissions granted to the role, with every wildcard pattern in `permissions` expanded to the `resource:action` pairs it matches. Adding an action to a resource shows up here as a change on the next plan.",
+		Computed:            true,
 	}
 	attributes["extends"] = schema.SetAttribute{
 		MarkdownDescription: "list of role keys that define what roles this role extends. In other words: this role will automatically inherit all the permissions of the given roles in this list.",
@@ -57,6 +70,9 @@ func (r *RoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 		},
 		Computed: true,
 		Optional: true,
+		Validators: []validator.Set{
+			setvalidator.ValueStringsAre(common.KeyFormatValidators()...),
+		},
 	}
 	attributes["resource"] = schema.StringAttribute{
 		MarkdownDescription: "The unique resource key that the role belongs to.",
@@ -64,20 +80,167 @@ func (r *RoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 		PlanModifiers: []planmodifier.String{
 			stringplanmodifier.RequiresReplace(),
 		},
+		Validators: common.KeyFormatValidators(),
 	}
 	attributes["resource_id"] = schema.StringAttribute{
 		MarkdownDescription: "The unique resource ID that the role belongs to.",
 		Computed:            true,
 	}
+	attributes["adopt_existing"] = common.AdoptExistingAttribute()
+	attributes["deletion_protection"] = common.DeletionProtectionAttribute()
+	attributes["wait_for_pdp"] = common.WaitForPDPAttribute()
 
 	resp.Schema = schema.Schema{
 		Attributes:          attributes,
 		MarkdownDescription: "See [the documentation](https://api.permit.io/v2/redoc#tag/Resources/operation/create_resource) for more information about roles.\n You can also read about Resource Roles [here](https://api.permit.io/v2/redoc#tag/Resource-Roles/operation/create_resource_role).",
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
+	}
+}
+
+// ModifyPlan expands wildcard permissions, checks extends for cycles, and warns
+// about the assignments and derivations that Permit removes together with the
+// role when it is destroyed or replaced.
+func (r *RoleResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if r.client.client == nil {
+		return
+	}
+
+	if !request.Plan.Raw.IsNull() {
+		r.planExpandedPermissions(ctx, request, response)
+		r.validatePlannedExtends(ctx, request, response)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	action, diags := common.PlannedRemoval(ctx, request, path.Root("key"), path.Root("resource"))
+	response.Diagnostics.Append(diags...)
+
+	if action == "" || response.Diagnostics.HasError() {
+		return
+	}
+
+	var state roleResourceModel
+
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	dependents, err := r.client.Dependents(ctx, state.roleModel)
+
+	if err != nil {
+		common.AddCascadeLookupWarning(&response.Diagnostics, action, "role", state.Key.ValueString(), err)
+		return
+	}
+
+	common.AddCascadeWarning(&response.Diagnostics, action, "role", state.Key.ValueString(), dependents...)
+}
+
+// planExpandedPermissions sets expanded_permissions in the plan. Patterns that
+// name a resource which does not exist yet, because it is created in the same
+// apply, leave it unknown until then.
+func (r *RoleResource) planExpandedPermissions(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	var permissions types.Set
+
+	response.Diagnostics.Append(request.Plan.GetAttribute(ctx, path.Root("permissions"), &permissions)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if permissions.IsUnknown() {
+		response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("expanded_permissions"), types.SetUnknown(types.StringType))...)
+		return
+	}
+
+	values, err := common.ConvertElementsToSlice[string](ctx, permissions.Elements())
+
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("permissions"), "Invalid permissions", err.Error())
+		return
+	}
+
+	expanded := values
+
+	if hasPermissionPatterns(values) {
+		actions, err := r.client.ResourceActions(ctx)
+
+		if err != nil {
+			response.Diagnostics.AddAttributeError(
+				path.Root("permissions"),
+				"Unable to expand permissions",
+				fmt.Errorf("unable to list resources to expand permission patterns: %w", err).Error(),
+			)
+			return
+		}
+
+		var missing []string
+		expanded, missing = expandPermissions(values, actions)
+
+		if len(missing) > 0 {
+			response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("expanded_permissions"), types.SetUnknown(types.StringType))...)
+			return
+		}
+	}
+
+	expandedSet, diags := types.SetValueFrom(ctx, types.StringType, expanded)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
 	}
+
+	response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("expanded_permissions"), expandedSet)...)
+}
+
+// validatePlannedExtends checks the planned extends of a role against the role
+// graph of the environment. It only loads the graph when extends changes.
+func (r *RoleResource) validatePlannedExtends(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	var plan roleResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+
+	if response.Diagnostics.HasError() || plan.Key.IsUnknown() || plan.Resource.IsUnknown() || plan.Extends.IsUnknown() {
+		return
+	}
+
+	if !request.State.Raw.IsNull() {
+		var state roleResourceModel
+
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+		if response.Diagnostics.HasError() || state.Extends.Equal(plan.Extends) {
+			return
+		}
+	}
+
+	extends, err := common.ConvertElementsToSlice[string](ctx, plan.Extends.Elements())
+
+	if err != nil || len(extends) == 0 {
+		return
+	}
+
+	graph, err := common.SharedRoleGraph(ctx, r.client.client)
+
+	if err != nil {
+		response.Diagnostics.AddAttributeWarning(
+			path.Root("extends"),
+			"Unable to validate extends",
+			fmt.Sprintf("Could not read the roles of the environment, so extends was not checked for cycles: %s", err),
+		)
+		return
+	}
+
+	validateExtends(graph, plan.Resource.ValueString(), plan.Key.ValueString(), extends, &response.Diagnostics)
 }
 
 func (r *RoleResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
-	var plan roleModel
+	var plan roleResourceModel
 
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
 
@@ -85,7 +248,23 @@ func (r *RoleResource) Create(ctx context.Context, request resource.CreateReques
 		return
 	}
 
-	roleRead, err := r.client.Create(ctx, plan)
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "role", plan.WaitForPDP, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	roleRead, err := r.client.Create(ctx, plan.roleModel)
+
+	if common.IsConflictErr(err) && common.ShouldAdoptExisting(plan.AdoptExisting) {
+		tflog.Info(ctx, fmt.Sprintf("Role %s already exists, adopting it", plan.Key.ValueString()))
+		roleRead, err = r.client.Adopt(ctx, plan.roleModel)
+		// The PDP already holds the adopted role, and loads no new policy
+		// version when it matches the plan.
+		policyWait = nil
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -95,11 +274,22 @@ func (r *RoleResource) Create(ctx context.Context, request resource.CreateReques
 		return
 	}
 
-	response.Diagnostics.Append(response.State.Set(ctx, roleRead)...)
+	roleRead.keepPermissionPatterns(ctx, plan.Permissions)
+	state := roleResourceModel{
+		roleModel:          roleRead,
+		AdoptExisting:      plan.AdoptExisting,
+		DeletionProtection: plan.DeletionProtection,
+		WaitForPDP:         plan.WaitForPDP,
+		Timeouts:           plan.Timeouts,
+	}
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+
+	r.awaitWrite(ctx, plan.roleModel, &response.Diagnostics)
+	policyWait.Await(ctx, &response.Diagnostics)
 }
 
 func (r *RoleResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
-	var model roleModel
+	var model roleResourceModel
 
 	response.Diagnostics.Append(request.State.Get(ctx, &model)...)
 
@@ -107,6 +297,9 @@ func (r *RoleResource) Read(ctx context.Context, request resource.ReadRequest, r
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
 	roleRead, err := r.client.Read(
 		ctx,
 		model.Key.ValueString(),
@@ -120,19 +313,37 @@ func (r *RoleResource) Read(ctx context.Context, request resource.ReadRequest, r
 		return
 	}
 
-	response.Diagnostics.Append(response.State.Set(ctx, &roleRead)...)
+	roleRead.keepPermissionPatterns(ctx, model.Permissions)
+	state := roleResourceModel{
+		roleModel:          roleRead,
+		AdoptExisting:      model.AdoptExisting,
+		DeletionProtection: common.DeletionProtectionFromState(model.DeletionProtection),
+		WaitForPDP:         model.WaitForPDP,
+		Timeouts:           model.Timeouts,
+	}
+	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
 }
 
 func (r *RoleResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	var plan roleModel
+	var plan, prior roleResourceModel
 
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &prior)...)
 
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	roleRead, err := r.client.Update(ctx, plan)
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Update, &response.Diagnostics)
+	defer cancel()
+
+	waitForPDP := types.BoolValue(plan.WaitForPDP.ValueBool() && prior.changesPolicy(plan.roleModel))
+	policyWait := common.StartPolicyWait(ctx, "role", waitForPDP, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	roleRead, err := r.client.Update(ctx, plan.roleModel)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -142,17 +353,48 @@ func (r *RoleResource) Update(ctx context.Context, request resource.UpdateReques
 		return
 	}
 
-	response.Diagnostics.Append(response.State.Set(ctx, roleRead)...)
+	roleRead.keepPermissionPatterns(ctx, plan.Permissions)
+	state := roleResourceModel{
+		roleModel:          roleRead,
+		AdoptExisting:      plan.AdoptExisting,
+		DeletionProtection: plan.DeletionProtection,
+		WaitForPDP:         plan.WaitForPDP,
+		Timeouts:           plan.Timeouts,
+	}
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+
+	r.awaitWrite(ctx, plan.roleModel, &response.Diagnostics)
+	policyWait.Await(ctx, &response.Diagnostics)
+}
+
+// awaitWrite waits until the role reads back with the planned values.
+func (r *RoleResource) awaitWrite(ctx context.Context, plan roleModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "role", func(ctx context.Context) (bool, error) {
+		read, err := r.client.Read(ctx, plan.Key.ValueString(), plan.Resource.ValueStringPointer())
+		return err == nil && read.matchesPlan(plan), err
+	}, diags)
 }
 
 func (r *RoleResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
-	var model roleModel
+	var model roleResourceModel
 	response.Diagnostics.Append(request.State.Get(ctx, &model)...)
 
 	if response.Diagnostics.HasError() {
 		return
 	}
 
+	if !common.CheckDeletionProtection(model.DeletionProtection, "role", model.Key.ValueString(), &response.Diagnostics) {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
+	policyWait := common.StartPolicyWait(ctx, "role", model.WaitForPDP, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.Delete(ctx, model.Key.ValueString(), model.Resource.ValueStringPointer())
 
 	if err != nil {
@@ -162,6 +404,8 @@ func (r *RoleResource) Delete(ctx context.Context, request resource.DeleteReques
 		)
 		return
 	}
+
+	policyWait.Await(ctx, &response.Diagnostics)
 }
 
 // ImportState implements resource.ResourceWithImportState.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -66,7 +67,8 @@ func (d *RoleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				Computed: true,
 			},
 			"key": schema.StringAttribute{
-				Required: true,
+				Required:   true,
+				Validators: common.KeyFormatValidators(),
 			},
 			"name": schema.StringAttribute{
 				Required: true,
@@ -80,14 +82,19 @@ func (d *RoleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				Optional:    true,
 				Computed:    true,
 			},
+			"expanded_permissions": schema.SetAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 			"extends": schema.SetAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
 				Computed:    true,
 			},
 			"resource": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
+				Optional:   true,
+				Computed:   true,
+				Validators: common.KeyFormatValidators(),
 			},
 			"resource_id": schema.StringAttribute{
 				Computed: true,
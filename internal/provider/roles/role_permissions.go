@@ -0,0 +1,111 @@
+package roles
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+// qualifiedPermissions returns the permissions of a role as resource:action
+// pairs. Resource roles may list bare actions of their own resource.
+func qualifiedPermissions(node *common.RoleNode) []string {
+	permissions := make([]string, 0, len(node.Permissions))
+	for _, permission := range node.Permissions {
+		if node.Resource != "" && !strings.Contains(permission, ":") {
+			permission = node.Resource + ":" + permission
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+// permissionGrant is one way in which a role ends up with a permission. Path
+// lists the roles followed from the requested role to the one that holds the
+// permission, starting with the requested role itself.
+type permissionGrant struct {
+	Permission string
+	Role       string
+	Resource   string
+	Derived    bool
+	Path       []string
+}
+
+// effectivePermissions follows the extends chains and role derivations from
+// the given role and returns every permission it grants, each with the
+// shortest path it was reached by. Cycles are followed once.
+func effectivePermissions(graph common.RoleGraph, start string) ([]permissionGrant, error) {
+	if _, ok := graph.Roles[start]; !ok {
+		return nil, fmt.Errorf("role %s not found", start)
+	}
+
+	type step struct {
+		id      string
+		path    []string
+		derived bool
+	}
+
+	derivations := graph.Derivations()
+	visited := map[string]bool{start: true}
+	queue := []step{{id: start, path: []string{start}}}
+	var grants []permissionGrant
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		node, ok := graph.Roles[current.id]
+		if !ok {
+			// Extends may name a role that was deleted since.
+			continue
+		}
+
+		for _, permission := range qualifiedPermissions(node) {
+			grants = append(grants, permissionGrant{
+				Permission: permission,
+				Role:       node.Key,
+				Resource:   node.Resource,
+				Derived:    current.derived,
+				Path:       current.path,
+			})
+		}
+
+		extends := append([]string{}, node.Extends...)
+		sort.Strings(extends)
+
+		for _, key := range extends {
+			next := common.RoleNodeId(node.Resource, key)
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			queue = append(queue, step{id: next, path: appendPath(current.path, next), derived: current.derived})
+		}
+
+		for _, derivation := range derivations[current.id] {
+			if visited[derivation.Target] {
+				continue
+			}
+			visited[derivation.Target] = true
+			queue = append(queue, step{
+				id:      derivation.Target,
+				path:    appendPath(current.path, fmt.Sprintf("%s via %s", derivation.Target, derivation.Relation)),
+				derived: true,
+			})
+		}
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		if grants[i].Permission != grants[j].Permission {
+			return grants[i].Permission < grants[j].Permission
+		}
+		return len(grants[i].Path) < len(grants[j].Path)
+	})
+
+	return grants, nil
+}
+
+func appendPath(path []string, next string) []string {
+	return append(append([]string{}, path...), next)
+}
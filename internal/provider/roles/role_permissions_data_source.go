@@ -0,0 +1,203 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+var (
+	_ datasource.DataSource              = &RolePermissionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &RolePermissionsDataSource{}
+)
+
+func NewRolePermissionsDataSource() datasource.DataSource {
+	return &RolePermissionsDataSource{}
+}
+
+type RolePermissionsDataSource struct {
+	client roleClient
+}
+
+type rolePermissionsModel struct {
+	Id                   types.String           `tfsdk:"id"`
+	Role                 types.String           `tfsdk:"role"`
+	Resource             types.String           `tfsdk:"resource"`
+	Permissions          []string               `tfsdk:"permissions"`
+	DirectPermissions    []string               `tfsdk:"direct_permissions"`
+	InheritedPermissions []string               `tfsdk:"inherited_permissions"`
+	Grants               []permissionGrantModel `tfsdk:"grants"`
+}
+
+type permissionGrantModel struct {
+	Permission types.String `tfsdk:"permission"`
+	Role       types.String `tfsdk:"role"`
+	Resource   types.String `tfsdk:"resource"`
+	Derived    types.Bool   `tfsdk:"derived"`
+	Path       []string     `tfsdk:"path"`
+}
+
+func (d *RolePermissionsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+	client, ok := request.ProviderData.(*permit.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *permit.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+		return
+	}
+	d.client.client = client
+}
+
+func (d *RolePermissionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_permissions"
+}
+
+func (d *RolePermissionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Computes the effective permissions of a role or resource role, following the roles it extends and the role derivations that grant other roles to its holders.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The role, as `role` for a top-level role or `resource#role` for a resource role.",
+			},
+			"role": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The key of the role.",
+				Validators:          common.KeyFormatValidators(),
+			},
+			"resource": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The key of the resource the role belongs to. Leave unset for a top-level role.",
+				Validators:          common.KeyFormatValidators(),
+			},
+			"permissions": schema.SetAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "Every `resource:action` pair the role grants, directly or inherited.",
+			},
+			"direct_permissions": schema.SetAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "The permissions set on the role itself.",
+			},
+			"inherited_permissions": schema.SetAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "The permissions that only come from extended or derived roles.",
+			},
+			"grants": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "How each permission is granted, one entry for every role that holds it, sorted by permission and shortest path first.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"permission": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The `resource:action` pair.",
+						},
+						"role": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The key of the role that holds the permission.",
+						},
+						"resource": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The resource of the role that holds the permission, empty for a top-level role.",
+						},
+						"derived": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the path follows a role derivation, so the permission applies on related resource instances rather than the one the role is assigned on.",
+						},
+						"path": schema.ListAttribute{
+							ElementType:         types.StringType,
+							Computed:            true,
+							MarkdownDescription: "The roles followed from the requested role to the one holding the permission. Derivation steps are written as `resource#role via relation`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RolePermissionsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var data rolePermissionsModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &data)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	graph, err := common.SharedRoleGraph(ctx, d.client.client)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to read roles",
+			fmt.Errorf("unable to read roles: %w", err).Error(),
+		)
+		return
+	}
+
+	start := common.RoleNodeId(data.Resource.ValueString(), data.Role.ValueString())
+	grants, err := effectivePermissions(graph, start)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to compute role permissions",
+			fmt.Errorf("unable to compute permissions of %s: %w", start, err).Error(),
+		)
+		return
+	}
+
+	data.Id = types.StringValue(start)
+	data.Permissions, data.DirectPermissions, data.InheritedPermissions = splitGrants(grants)
+	data.Grants = make([]permissionGrantModel, 0, len(grants))
+
+	for _, grant := range grants {
+		data.Grants = append(data.Grants, permissionGrantModel{
+			Permission: types.StringValue(grant.Permission),
+			Role:       types.StringValue(grant.Role),
+			Resource:   types.StringValue(grant.Resource),
+			Derived:    types.BoolValue(grant.Derived),
+			Path:       grant.Path,
+		})
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
+}
+
+// splitGrants returns every permission in grants, those granted by the
+// requested role itself, and those that are only inherited.
+func splitGrants(grants []permissionGrant) ([]string, []string, []string) {
+	all := []string{}
+	direct := []string{}
+	isDirect := map[string]bool{}
+	seen := map[string]bool{}
+
+	for _, grant := range grants {
+		if len(grant.Path) == 1 && !isDirect[grant.Permission] {
+			isDirect[grant.Permission] = true
+			direct = append(direct, grant.Permission)
+		}
+		if !seen[grant.Permission] {
+			seen[grant.Permission] = true
+			all = append(all, grant.Permission)
+		}
+	}
+
+	inherited := []string{}
+	for _, permission := range all {
+		if !isDirect[permission] {
+			inherited = append(inherited, permission)
+		}
+	}
+
+	return all, direct, inherited
+}
@@ -0,0 +1,68 @@
+package roles
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+func testRoleGraph() common.RoleGraph {
+	graph := common.RoleGraph{Roles: map[string]*common.RoleNode{}}
+	for _, node := range []*common.RoleNode{
+		{Key: "admin", Permissions: []string{"billing:manage"}, Extends: []string{"viewer"}},
+		{Key: "viewer", Permissions: []string{"billing:read"}},
+		{Resource: "folder", Key: "owner", Permissions: []string{"folder:delete"}, Extends: []string{"editor"}},
+		{Resource: "folder", Key: "editor", Permissions: []string{"folder:write"}, Extends: []string{"owner"}},
+		{Resource: "document", Key: "editor", Permissions: []string{"write", "document:read"}, GrantedTo: []models.DerivedRoleRuleRead{
+			{Role: "editor", OnResource: "folder", LinkedByRelation: "parent"},
+		}},
+	} {
+		graph.Roles[node.Id()] = node
+	}
+	return graph
+}
+
+func TestEffectivePermissions(t *testing.T) {
+	grants, err := effectivePermissions(testRoleGraph(), "folder#owner")
+	if err != nil {
+		t.Fatalf("effectivePermissions() error = %v", err)
+	}
+
+	want := []permissionGrant{
+		{Permission: "document:read", Role: "editor", Resource: "document", Derived: true, Path: []string{"folder#owner", "folder#editor", "document#editor via parent"}},
+		{Permission: "document:write", Role: "editor", Resource: "document", Derived: true, Path: []string{"folder#owner", "folder#editor", "document#editor via parent"}},
+		{Permission: "folder:delete", Role: "owner", Resource: "folder", Path: []string{"folder#owner"}},
+		{Permission: "folder:write", Role: "editor", Resource: "folder", Path: []string{"folder#owner", "folder#editor"}},
+	}
+
+	if !reflect.DeepEqual(grants, want) {
+		t.Errorf("effectivePermissions() = %+v, want %+v", grants, want)
+	}
+}
+
+func TestEffectivePermissionsTopLevel(t *testing.T) {
+	grants, err := effectivePermissions(testRoleGraph(), "admin")
+	if err != nil {
+		t.Fatalf("effectivePermissions() error = %v", err)
+	}
+
+	all, direct, inherited := splitGrants(grants)
+
+	if want := []string{"billing:manage", "billing:read"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("permissions = %q, want %q", all, want)
+	}
+	if want := []string{"billing:manage"}; !reflect.DeepEqual(direct, want) {
+		t.Errorf("direct = %q, want %q", direct, want)
+	}
+	if want := []string{"billing:read"}; !reflect.DeepEqual(inherited, want) {
+		t.Errorf("inherited = %q, want %q", inherited, want)
+	}
+}
+
+func TestEffectivePermissionsUnknownRole(t *testing.T) {
+	if _, err := effectivePermissions(testRoleGraph(), "document#owner"); err == nil {
+		t.Error("effectivePermissions() error = nil, want an error for a missing role")
+	}
+}
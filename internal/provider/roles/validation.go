@@ -0,0 +1,44 @@
+package roles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+// validateExtends overlays the planned extends of a role on the graph. A cycle
+// is an error. A role that does not exist is only a warning, since it is often
+// created in the same apply.
+func validateExtends(graph common.RoleGraph, resource string, key string, extends []string, diags *diag.Diagnostics) {
+	id := common.RoleNodeId(resource, key)
+	node := &common.RoleNode{Resource: resource, Key: key}
+
+	if existing, ok := graph.Roles[id]; ok {
+		copied := *existing
+		node = &copied
+	}
+
+	node.Extends = extends
+	graph = graph.WithRole(node)
+
+	for _, extended := range extends {
+		if _, ok := graph.Roles[common.RoleNodeId(resource, extended)]; !ok {
+			diags.AddAttributeWarning(
+				path.Root("extends"),
+				"Extended role not found",
+				fmt.Sprintf("Role %q extends %q, which does not exist yet. This is expected when it is created in the same apply; otherwise the apply will fail.", id, common.RoleNodeId(resource, extended)),
+			)
+		}
+	}
+
+	if cycle := graph.FindExtendsCycle(id); cycle != nil {
+		diags.AddAttributeError(
+			path.Root("extends"),
+			"Cyclic role extends",
+			fmt.Sprintf("Role %q would extend itself: %s.", id, strings.Join(cycle, " -> ")),
+		)
+	}
+}
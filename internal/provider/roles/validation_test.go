@@ -0,0 +1,55 @@
+package roles
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+func TestValidateExtends(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		extends      []string
+		wantWarnings int
+		wantErrors   int
+	}{
+		{"existing role", "admin", []string{"viewer"}, 0, 0},
+		{"new role", "auditor", []string{"viewer"}, 0, 0},
+		{"missing role", "admin", []string{"billing"}, 1, 0},
+		{"cycle", "viewer", []string{"admin"}, 0, 1},
+		{"self", "viewer", []string{"viewer"}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := testRoleGraph()
+			var diags diag.Diagnostics
+
+			validateExtends(graph, "", tt.key, tt.extends, &diags)
+
+			if got := diags.WarningsCount(); got != tt.wantWarnings {
+				t.Errorf("warnings = %d, want %d: %v", got, tt.wantWarnings, diags)
+			}
+			if got := diags.ErrorsCount(); got != tt.wantErrors {
+				t.Errorf("errors = %d, want %d: %v", got, tt.wantErrors, diags)
+			}
+		})
+	}
+}
+
+func TestValidateExtendsDoesNotChangeExistingRoles(t *testing.T) {
+	graph := testRoleGraph()
+	original := graph.Roles["admin"]
+	var diags diag.Diagnostics
+
+	validateExtends(graph, "", "admin", []string{}, &diags)
+
+	if len(original.Extends) != 1 {
+		t.Errorf("validateExtends() changed the loaded role: %v", original.Extends)
+	}
+	if _, ok := graph.Roles[common.RoleNodeId("", "admin")]; !ok {
+		t.Errorf("validateExtends() removed the planned role")
+	}
+}
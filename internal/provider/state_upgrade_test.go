@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// TestResourceStateUpgraders checks that every resource can upgrade state
+// written with any earlier version of its schema.
+func TestResourceStateUpgraders(t *testing.T) {
+	ctx := context.Background()
+
+	for _, newResource := range permitProvider.Resources(ctx) {
+		r := newResource()
+
+		var metadata resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "permitio"}, &metadata)
+
+		t.Run(metadata.TypeName, func(t *testing.T) {
+			var schemaResponse resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResponse)
+
+			upgradable, ok := r.(resource.ResourceWithUpgradeState)
+			if !ok {
+				if schemaResponse.Schema.Version > 0 {
+					t.Fatalf("%s is at schema version %d but does not implement resource.ResourceWithUpgradeState", metadata.TypeName, schemaResponse.Schema.Version)
+				}
+				return
+			}
+
+			upgraders := upgradable.UpgradeState(ctx)
+			for version := int64(0); version < schemaResponse.Schema.Version; version++ {
+				if _, ok := upgraders[version]; !ok {
+					t.Errorf("no state upgrader from version %d to %d", version, schemaResponse.Schema.Version)
+				}
+			}
+			for version := range upgraders {
+				if version >= schemaResponse.Schema.Version {
+					t.Errorf("state upgrader from version %d is not older than the schema version %d", version, schemaResponse.Schema.Version)
+				}
+			}
+		})
+	}
+}
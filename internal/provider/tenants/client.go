@@ -2,9 +2,9 @@ package tenants
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type tenantClient struct {
@@ -12,13 +12,9 @@ type tenantClient struct {
 }
 
 func (c *tenantClient) Create(ctx context.Context, plan tenantModel) (tenantModel, error) {
-	// Parse attributes from JSON string
-	var attributes map[string]interface{}
-	if !plan.Attributes.IsNull() && plan.Attributes.ValueString() != "" {
-		err := json.Unmarshal([]byte(plan.Attributes.ValueString()), &attributes)
-		if err != nil {
-			return tenantModel{}, err
-		}
+	attributes, err := common.AttributesFromDynamic(ctx, plan.Attributes)
+	if err != nil {
+		return tenantModel{}, err
 	}
 
 	tenantCreate := models.TenantCreate{
@@ -34,7 +30,23 @@ func (c *tenantClient) Create(ctx context.Context, plan tenantModel) (tenantMode
 		return tenantModel{}, err
 	}
 
-	return tfModelFromTenantRead(*createdTenant), nil
+	return tfModelFromTenantRead(*createdTenant)
+}
+
+// Adopt takes over a tenant that already exists with the planned key, updating
+// it when it differs from the plan.
+func (c *tenantClient) Adopt(ctx context.Context, plan tenantModel) (tenantModel, error) {
+	existing, err := c.Read(ctx, plan.Key.ValueString())
+
+	if err != nil {
+		return tenantModel{}, err
+	}
+
+	if existing.matchesPlan(ctx, plan) {
+		return existing, nil
+	}
+
+	return c.Update(ctx, plan, tenantModel{})
 }
 
 func (c *tenantClient) Read(ctx context.Context, key string) (tenantModel, error) {
@@ -44,17 +56,16 @@ func (c *tenantClient) Read(ctx context.Context, key string) (tenantModel, error
 		return tenantModel{}, err
 	}
 
-	return tfModelFromTenantRead(*tenantRead), nil
+	return tfModelFromTenantRead(*tenantRead)
 }
 
-func (c *tenantClient) Update(ctx context.Context, plan tenantModel) (tenantModel, error) {
-	// Parse attributes from JSON string
-	var attributes map[string]interface{}
-	if !plan.Attributes.IsNull() && plan.Attributes.ValueString() != "" {
-		err := json.Unmarshal([]byte(plan.Attributes.ValueString()), &attributes)
-		if err != nil {
-			return tenantModel{}, err
-		}
+// Update writes the plan to the tenant. prior is the state before the update;
+// in merge mode the attributes it managed that are no longer planned are
+// removed, and every other key set outside of Terraform is kept.
+func (c *tenantClient) Update(ctx context.Context, plan tenantModel, prior tenantModel) (tenantModel, error) {
+	attributes, err := c.attributesToWrite(ctx, plan, prior)
+	if err != nil {
+		return tenantModel{}, err
 	}
 
 	tenantUpdate := models.TenantUpdate{
@@ -69,9 +80,70 @@ func (c *tenantClient) Update(ctx context.Context, plan tenantModel) (tenantMode
 		return tenantModel{}, err
 	}
 
-	return tfModelFromTenantRead(*updatedTenant), nil
+	return tfModelFromTenantRead(*updatedTenant)
+}
+
+func (c *tenantClient) attributesToWrite(ctx context.Context, plan tenantModel, prior tenantModel) (map[string]any, error) {
+	attributes, err := common.AttributesFromDynamic(ctx, plan.Attributes)
+	if err != nil || !common.IsMergeMode(plan.AttributesMode) {
+		return attributes, err
+	}
+
+	// Keys are only removed when they were managed in merge mode before. After
+	// switching from authoritative mode every key is in state, and dropping the
+	// unconfigured ones would erase exactly what merge mode is meant to keep.
+	var priorAttributes map[string]any
+	if common.IsMergeMode(prior.AttributesMode) {
+		priorAttributes, err = common.AttributesFromDynamic(ctx, prior.Attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := c.client.Api.Tenants.Get(ctx, plan.Key.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	return common.MergeAttributes(current.Attributes, priorAttributes, attributes), nil
 }
 
 func (c *tenantClient) Delete(ctx context.Context, key string) error {
 	return c.client.Api.Tenants.Delete(ctx, key)
 }
+
+// Dependents lists what Permit removes together with the tenant: the role
+// assignments and resource instances that belong to it.
+func (c *tenantClient) Dependents(ctx context.Context, key string) ([]string, error) {
+	assignments, err := common.ListAllRoleAssignments(ctx, c.client, "", "", key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := common.ListAllPages(func(page int, perPage int) ([]models.ResourceInstanceRead, error) {
+		instances, err := c.client.Api.ResourceInstances.List(ctx, page, perPage, key, "", "")
+
+		if err != nil || instances == nil {
+			return nil, err
+		}
+
+		return *instances, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []string
+
+	if len(assignments) > 0 {
+		dependents = append(dependents, common.Pluralize(len(assignments), "role assignment"))
+	}
+
+	if len(instances) > 0 {
+		dependents = append(dependents, common.Pluralize(len(instances), "resource instance"))
+	}
+
+	return dependents, nil
+}
@@ -1,26 +1,44 @@
 package tenants
 
 import (
-	"encoding/json"
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type tenantModel struct {
-	Id             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	ProjectId      types.String `tfsdk:"project_id"`
-	EnvironmentId  types.String `tfsdk:"environment_id"`
-	CreatedAt      types.String `tfsdk:"created_at"`
-	UpdatedAt      types.String `tfsdk:"updated_at"`
-	LastActionAt   types.String `tfsdk:"last_action_at"`
-	Key            types.String `tfsdk:"key"`
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	Attributes     types.String `tfsdk:"attributes"`
+	Id             types.String  `tfsdk:"id"`
+	OrganizationId types.String  `tfsdk:"organization_id"`
+	ProjectId      types.String  `tfsdk:"project_id"`
+	EnvironmentId  types.String  `tfsdk:"environment_id"`
+	CreatedAt      types.String  `tfsdk:"created_at"`
+	UpdatedAt      types.String  `tfsdk:"updated_at"`
+	LastActionAt   types.String  `tfsdk:"last_action_at"`
+	Key            types.String  `tfsdk:"key"`
+	Name           types.String  `tfsdk:"name"`
+	Description    types.String  `tfsdk:"description"`
+	Attributes     types.Dynamic `tfsdk:"attributes"`
+	AttributesMode types.String  `tfsdk:"attributes_mode"`
+	AdoptExisting  types.Bool    `tfsdk:"adopt_existing"`
+
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// matchesPlan reports whether a tenant read from the API already has the values
+// set in the plan, so adopting it needs no update. In merge mode only the
+// planned attribute keys are compared.
+func (m *tenantModel) matchesPlan(ctx context.Context, plan tenantModel) bool {
+	attributes := common.AttributesForState(ctx, plan.AttributesMode, plan.Attributes, m.Attributes)
+
+	return common.PlanValueMatches(plan.Name, m.Name) &&
+		common.PlanValueMatches(plan.Description, m.Description) &&
+		(plan.Attributes.IsUnknown() || common.DynamicAttributesEqual(ctx, plan.Attributes, attributes))
 }
 
-func tfModelFromTenantRead(m models.TenantRead) tenantModel {
+func tfModelFromTenantRead(m models.TenantRead) (tenantModel, error) {
 	r := tenantModel{}
 	r.Id = types.StringValue(m.Id)
 	r.Key = types.StringValue(m.Key)
@@ -33,17 +51,11 @@ func tfModelFromTenantRead(m models.TenantRead) tenantModel {
 	r.UpdatedAt = types.StringValue(m.UpdatedAt.String())
 	r.LastActionAt = types.StringValue(m.LastActionAt.String())
 
-	// Convert attributes map to JSON string
-	if len(m.Attributes) > 0 {
-		attributesJSON, err := json.Marshal(m.Attributes)
-		if err == nil {
-			r.Attributes = types.StringValue(string(attributesJSON))
-		} else {
-			r.Attributes = types.StringValue("{}")
-		}
-	} else {
-		r.Attributes = types.StringNull()
+	attributes, err := common.AttributesToDynamic(m.Attributes)
+	if err != nil {
+		return tenantModel{}, err
 	}
+	r.Attributes = attributes
 
-	return r
+	return r, nil
 }
@@ -3,15 +3,23 @@ package tenants
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &TenantResource{}
-	_ resource.ResourceWithConfigure = &TenantResource{}
+	_ resource.Resource                 = &TenantResource{}
+	_ resource.ResourceWithConfigure    = &TenantResource{}
+	_ resource.ResourceWithUpgradeState = &TenantResource{}
+	_ resource.ResourceWithImportState  = &TenantResource{}
+	_ resource.ResourceWithModifyPlan   = &TenantResource{}
 )
 
 func NewTenantResource() resource.Resource {
@@ -39,18 +47,74 @@ func (r *TenantResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 		Computed:            true,
 	}
 
-	attributes["attributes"] = schema.StringAttribute{
-		MarkdownDescription: "Arbitrary tenant attributes in JSON format that will be used to enforce attribute-based access control policies.",
+	attributes["attributes"] = schema.DynamicAttribute{
+		MarkdownDescription: "Arbitrary tenant attributes that will be used to enforce attribute-based access control policies, as an object such as `{ plan = \"pro\", seats = 10 }`. A JSON-encoded string is still accepted but deprecated. Every key must be declared as a tenant attribute in Permit and hold a value of its declared type; mismatches are reported as warnings when planning, as the schema may change in the same apply.",
 		Optional:            true,
 		Computed:            true,
+		Validators: []validator.Dynamic{
+			common.AttributesValidator(),
+		},
 	}
 
+	attributes["attributes_mode"] = common.AttributesModeAttribute()
+	attributes["adopt_existing"] = common.AdoptExistingAttribute()
+	attributes["deletion_protection"] = common.DeletionProtectionAttribute()
+
 	resp.Schema = schema.Schema{
+		Version:             1,
 		Attributes:          attributes,
 		MarkdownDescription: "Manages a Permit.io tenant. Tenants represent isolated groups or organizations within your application. See [the documentation](https://api.permit.io/v2/redoc#tag/Tenants) for more information about tenants.",
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
+// ModifyPlan checks the configured attributes against the attribute schema of
+// tenants, and warns about the assignments and instances that Permit removes
+// together with the tenant when it is destroyed or replaced.
+func (r *TenantResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if r.client.client == nil {
+		return
+	}
+
+	if !request.Plan.Raw.IsNull() {
+		var attributes types.Dynamic
+
+		response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("attributes"), &attributes)...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		common.ValidateAttributesSchema(ctx, r.client.client, common.TenantResourceKey, attributes, &response.Diagnostics)
+	}
+
+	action, diags := common.PlannedRemoval(ctx, request, path.Root("key"))
+	response.Diagnostics.Append(diags...)
+
+	if action == "" || response.Diagnostics.HasError() {
+		return
+	}
+
+	var state tenantModel
+
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	dependents, err := r.client.Dependents(ctx, state.Key.ValueString())
+
+	if err != nil {
+		common.AddCascadeLookupWarning(&response.Diagnostics, action, "tenant", state.Key.ValueString(), err)
+		return
+	}
+
+	common.AddCascadeWarning(&response.Diagnostics, action, "tenant", state.Key.ValueString(), dependents...)
+}
+
 func (r *TenantResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	var plan tenantModel
 
@@ -60,8 +124,16 @@ func (r *TenantResource) Create(ctx context.Context, request resource.CreateRequ
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
 	tenantRead, err := r.client.Create(ctx, plan)
 
+	if common.IsConflictErr(err) && common.ShouldAdoptExisting(plan.AdoptExisting) {
+		tflog.Info(ctx, fmt.Sprintf("Tenant %s already exists, adopting it", plan.Key.ValueString()))
+		tenantRead, err = r.client.Adopt(ctx, plan)
+	}
+
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Unable to create tenant",
@@ -70,7 +142,14 @@ func (r *TenantResource) Create(ctx context.Context, request resource.CreateRequ
 		return
 	}
 
+	tenantRead.Attributes = common.AttributesForState(ctx, plan.AttributesMode, plan.Attributes, tenantRead.Attributes)
+	tenantRead.AttributesMode = plan.AttributesMode
+	tenantRead.AdoptExisting = plan.AdoptExisting
+	tenantRead.DeletionProtection = plan.DeletionProtection
+	tenantRead.Timeouts = plan.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, tenantRead)...)
+
+	r.awaitWrite(ctx, plan, &response.Diagnostics)
 }
 
 func (r *TenantResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -82,6 +161,9 @@ func (r *TenantResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
 	tenantRead, err := r.client.Read(ctx, model.Key.ValueString())
 
 	if err != nil {
@@ -92,19 +174,28 @@ func (r *TenantResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 
+	tenantRead.AttributesMode = common.AttributesModeFromState(model.AttributesMode)
+	tenantRead.Attributes = common.AttributesForState(ctx, tenantRead.AttributesMode, model.Attributes, tenantRead.Attributes)
+	tenantRead.AdoptExisting = model.AdoptExisting
+	tenantRead.DeletionProtection = common.DeletionProtectionFromState(model.DeletionProtection)
+	tenantRead.Timeouts = model.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, &tenantRead)...)
 }
 
 func (r *TenantResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	var plan tenantModel
+	var plan, state tenantModel
 
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
 
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	tenantRead, err := r.client.Update(ctx, plan)
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Update, &response.Diagnostics)
+	defer cancel()
+
+	tenantRead, err := r.client.Update(ctx, plan, state)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -114,7 +205,22 @@ func (r *TenantResource) Update(ctx context.Context, request resource.UpdateRequ
 		return
 	}
 
+	tenantRead.Attributes = common.AttributesForState(ctx, plan.AttributesMode, plan.Attributes, tenantRead.Attributes)
+	tenantRead.AttributesMode = plan.AttributesMode
+	tenantRead.AdoptExisting = plan.AdoptExisting
+	tenantRead.DeletionProtection = plan.DeletionProtection
+	tenantRead.Timeouts = plan.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, tenantRead)...)
+
+	r.awaitWrite(ctx, plan, &response.Diagnostics)
+}
+
+// awaitWrite waits until the tenant reads back with the planned values.
+func (r *TenantResource) awaitWrite(ctx context.Context, plan tenantModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "tenant", func(ctx context.Context) (bool, error) {
+		read, err := r.client.Read(ctx, plan.Key.ValueString())
+		return err == nil && read.matchesPlan(ctx, plan), err
+	}, diags)
 }
 
 func (r *TenantResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -125,6 +231,13 @@ func (r *TenantResource) Delete(ctx context.Context, request resource.DeleteRequ
 		return
 	}
 
+	if !common.CheckDeletionProtection(model.DeletionProtection, "tenant", model.Key.ValueString(), &response.Diagnostics) {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
 	err := r.client.Delete(ctx, model.Key.ValueString())
 
 	if err != nil {
@@ -134,3 +247,17 @@ func (r *TenantResource) Delete(ctx context.Context, request resource.DeleteRequ
 		)
 	}
 }
+
+// ImportState implements resource.ResourceWithImportState. The import ID is the
+// tenant key.
+func (r *TenantResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
+
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (r *TenantResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored attributes as a JSON string.
+		0: common.JSONStringAttributesUpgrader("attributes"),
+	}
+}
@@ -0,0 +1,69 @@
+package tenants
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// tenantStateV0 is state written by provider versions that stored attributes
+// as a JSON string.
+const tenantStateV0 = `{
+	"id": "2b9c0f3e",
+	"organization_id": "org",
+	"project_id": "project",
+	"environment_id": "env",
+	"created_at": "2024-01-01 00:00:00 +0000 UTC",
+	"updated_at": "2024-01-01 00:00:00 +0000 UTC",
+	"last_action_at": "2024-01-01 00:00:00 +0000 UTC",
+	"key": "acme-corp",
+	"name": "Acme Corporation",
+	"description": null,
+	"attributes": "{\"region\":\"us-west\",\"seats\":10}",
+	"adopt_existing": null,
+	"deletion_protection": false
+}`
+
+func TestUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &TenantResource{}
+
+	var schemaResponse resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResponse)
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("UpgradeState() has no upgrader for version 0")
+	}
+
+	request := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(tenantStateV0)}}
+	var response resource.UpgradeStateResponse
+	upgrader.StateUpgrader(ctx, request, &response)
+
+	if response.Diagnostics.HasError() {
+		t.Fatalf("StateUpgrader() diagnostics = %v", response.Diagnostics)
+	}
+
+	raw, err := response.DynamicValue.Unmarshal(schemaResponse.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %v", err)
+	}
+
+	var model tenantModel
+	state := tfsdk.State{Schema: schemaResponse.Schema, Raw: raw}
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("State.Get() diagnostics = %v", diags)
+	}
+
+	want := types.DynamicValue(types.StringValue(`{"region":"us-west","seats":10}`))
+	if !model.Attributes.Equal(want) {
+		t.Errorf("Attributes = %v, want %v", model.Attributes, want)
+	}
+	if model.Key.ValueString() != "acme-corp" || model.Name.ValueString() != "Acme Corporation" {
+		t.Errorf("upgraded state = %+v, want the v0 attributes preserved", model)
+	}
+}
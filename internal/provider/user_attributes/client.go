@@ -5,6 +5,7 @@ import (
 
 	"github.com/permitio/permit-golang/pkg/models"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type userAttributesClient struct {
@@ -24,6 +25,12 @@ func (c *userAttributesClient) Create(ctx context.Context, plan userAttributeMod
 	attributeCreate.SetType(*attributeType)
 	attributeCreate.SetDescription(plan.Description.ValueString())
 
+	unlock, err := common.LockResource(ctx, UserKey)
+	if err != nil {
+		return userAttributeModel{}, err
+	}
+	defer unlock()
+
 	createdAttribute, err := c.client.Api.ResourceAttributes.Create(ctx, UserKey, attributeCreate)
 
 	if err != nil {
@@ -33,6 +40,22 @@ func (c *userAttributesClient) Create(ctx context.Context, plan userAttributeMod
 	return tfModelFromSDK(*createdAttribute), nil
 }
 
+// Adopt takes over a user attribute that already exists with the planned key,
+// updating it when it differs from the plan.
+func (c *userAttributesClient) Adopt(ctx context.Context, plan userAttributeModel) (userAttributeModel, error) {
+	existing, err := c.Read(ctx, plan.Key.ValueString())
+
+	if err != nil {
+		return userAttributeModel{}, err
+	}
+
+	if existing.matchesPlan(plan) {
+		return existing, nil
+	}
+
+	return c.Update(ctx, plan.Key.ValueString(), plan)
+}
+
 func (c *userAttributesClient) Read(ctx context.Context, key string) (userAttributeModel, error) {
 	readAttribute, err := c.client.Api.ResourceAttributes.Get(ctx, UserKey, key)
 
@@ -44,6 +67,12 @@ func (c *userAttributesClient) Read(ctx context.Context, key string) (userAttrib
 }
 
 func (c *userAttributesClient) Delete(ctx context.Context, key string) error {
+	unlock, err := common.LockResource(ctx, UserKey)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return c.client.Api.ResourceAttributes.Delete(ctx, UserKey, key)
 }
 
@@ -58,6 +87,12 @@ func (c *userAttributesClient) Update(ctx context.Context, key string, plan user
 	attributeUpdate.SetType(*attributeType)
 	attributeUpdate.SetDescription(plan.Description.ValueString())
 
+	unlock, err := common.LockResource(ctx, UserKey)
+	if err != nil {
+		return userAttributeModel{}, err
+	}
+	defer unlock()
+
 	updatedAttribute, err := c.client.Api.ResourceAttributes.Update(ctx, UserKey, key, attributeUpdate)
 
 	if err != nil {
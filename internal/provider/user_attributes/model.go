@@ -1,8 +1,10 @@
 package user_attributes
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 const UserKey = "__user"
@@ -22,6 +24,16 @@ type userAttributeModel struct {
 	Type        types.String `tfsdk:"type"`
 	Key         types.String `tfsdk:"key"`
 	Description types.String `tfsdk:"description"`
+
+	AdoptExisting types.Bool     `tfsdk:"adopt_existing"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// matchesPlan reports whether a user attribute read from the API already has
+// the type and description set in the plan, so adopting it needs no update.
+func (m *userAttributeModel) matchesPlan(plan userAttributeModel) bool {
+	return common.PlanValueMatches(plan.Type, m.Type) &&
+		common.PlanValueMatches(plan.Description, m.Description)
 }
 
 func tfModelFromSDK(m models.ResourceAttributeRead) userAttributeModel {
@@ -2,17 +2,22 @@ package user_attributes
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &UserAttributeResource{}
-	_ resource.ResourceWithConfigure = &UserAttributeResource{}
+	_ resource.Resource                = &UserAttributeResource{}
+	_ resource.ResourceWithConfigure   = &UserAttributeResource{}
+	_ resource.ResourceWithImportState = &UserAttributeResource{}
 )
 
 func NewUserAttributeResource() resource.Resource {
@@ -50,6 +55,7 @@ func (c *UserAttributeResource) Schema(_ context.Context, _ resource.SchemaReque
 	attributes["key"] = schema.StringAttribute{
 		Required:            true,
 		MarkdownDescription: "The key of the attribute",
+		Validators:          common.KeyValidators(),
 	}
 	attributes["type"] = schema.StringAttribute{
 		Required:            true,
@@ -62,10 +68,14 @@ func (c *UserAttributeResource) Schema(_ context.Context, _ resource.SchemaReque
 		Required:            true,
 		MarkdownDescription: "The description of the attribute",
 	}
+	attributes["adopt_existing"] = common.AdoptExistingAttribute()
 
 	response.Schema = schema.Schema{
 		Attributes:          attributes,
 		MarkdownDescription: "See [the documentation](https://api.permit.io/v2/redoc#tag/User-Attributes/operation/create_user_attribute) for more information about User Attributes",
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
 	}
 }
 
@@ -78,8 +88,16 @@ func (c *UserAttributeResource) Create(ctx context.Context, request resource.Cre
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Create, &response.Diagnostics)
+	defer cancel()
+
 	reality, err := c.client.Create(ctx, model)
 
+	if common.IsConflictErr(err) && common.ShouldAdoptExisting(model.AdoptExisting) {
+		tflog.Info(ctx, fmt.Sprintf("User attribute %s already exists, adopting it", model.Key.ValueString()))
+		reality, err = c.client.Adopt(ctx, model)
+	}
+
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Failed creating user attribute",
@@ -88,7 +106,15 @@ func (c *UserAttributeResource) Create(ctx context.Context, request resource.Cre
 		return
 	}
 
+	reality.AdoptExisting = model.AdoptExisting
+	reality.Timeouts = model.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, reality)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	c.awaitWrite(ctx, model, &response.Diagnostics)
 }
 
 func (c *UserAttributeResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -100,6 +126,9 @@ func (c *UserAttributeResource) Read(ctx context.Context, request resource.ReadR
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Read, &response.Diagnostics)
+	defer cancel()
+
 	reality, err := c.client.Read(ctx, model.Key.ValueString())
 
 	if err != nil {
@@ -110,6 +139,8 @@ func (c *UserAttributeResource) Read(ctx context.Context, request resource.ReadR
 		return
 	}
 
+	reality.AdoptExisting = model.AdoptExisting
+	reality.Timeouts = model.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, reality)...)
 }
 
@@ -122,6 +153,9 @@ func (c *UserAttributeResource) Update(ctx context.Context, request resource.Upd
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Update, &response.Diagnostics)
+	defer cancel()
+
 	reality, err := c.client.Update(ctx, model.Id.ValueString(), model)
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -131,7 +165,15 @@ func (c *UserAttributeResource) Update(ctx context.Context, request resource.Upd
 		return
 	}
 
+	reality.AdoptExisting = model.AdoptExisting
+	reality.Timeouts = model.Timeouts
 	response.Diagnostics.Append(response.State.Set(ctx, reality)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	c.awaitWrite(ctx, model, &response.Diagnostics)
 }
 
 func (c *UserAttributeResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -143,6 +185,9 @@ func (c *UserAttributeResource) Delete(ctx context.Context, request resource.Del
 		return
 	}
 
+	ctx, cancel := common.OperationContext(ctx, model.Timeouts.Delete, &response.Diagnostics)
+	defer cancel()
+
 	err := c.client.Delete(ctx, model.Key.ValueString())
 
 	if err != nil {
@@ -153,3 +198,18 @@ func (c *UserAttributeResource) Delete(ctx context.Context, request resource.Del
 		return
 	}
 }
+
+// awaitWrite waits until the user attribute reads back with the planned type
+// and description.
+func (c *UserAttributeResource) awaitWrite(ctx context.Context, plan userAttributeModel, diags *diag.Diagnostics) {
+	common.AwaitWrite(ctx, "user attribute", func(ctx context.Context) (bool, error) {
+		read, err := c.client.Read(ctx, plan.Key.ValueString())
+		return err == nil && read.matchesPlan(plan), err
+	}, diags)
+}
+
+// ImportState implements resource.ResourceWithImportState. The import ID is the
+// user attribute key.
+func (c *UserAttributeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
@@ -0,0 +1,105 @@
+package user_roles
+
+import (
+	"context"
+
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+type userRolesClient struct {
+	client *permit.Client
+}
+
+// List returns every role the user has in the tenant, including resource
+// instance roles and roles granted outside Terraform.
+func (c *userRolesClient) List(ctx context.Context, user string, tenant string) ([]assignment, error) {
+	reads, err := common.ListAllRoleAssignments(ctx, c.client, user, "", tenant)
+
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]assignment, 0, len(reads))
+	for _, read := range reads {
+		assignments = append(assignments, assignmentFromRead(read))
+	}
+
+	return assignments, nil
+}
+
+// Reconcile makes the user's roles in the tenant exactly match desired,
+// assigning missing roles and unassigning every other role. Missing roles are
+// assigned first, so that replacing a role never leaves the user without
+// access in between. It returns the roles it unassigned.
+func (c *userRolesClient) Reconcile(ctx context.Context, user string, tenant string, desired []assignment) ([]assignment, error) {
+	current, err := c.List(ctx, user, tenant)
+
+	if err != nil {
+		return nil, err
+	}
+
+	toAssign, toUnassign := diffAssignments(desired, current)
+
+	for _, a := range toAssign {
+		if err := c.assign(ctx, user, tenant, a); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range toUnassign {
+		if err := c.unassign(ctx, user, tenant, a); err != nil {
+			return nil, err
+		}
+	}
+
+	return toUnassign, nil
+}
+
+// Unassign removes the given roles from the user in the tenant. Roles that
+// were already removed are skipped.
+func (c *userRolesClient) Unassign(ctx context.Context, user string, tenant string, assignments []assignment) error {
+	current, err := c.List(ctx, user, tenant)
+
+	if err != nil {
+		return err
+	}
+
+	present := make(map[assignment]struct{}, len(current))
+	for _, a := range current {
+		present[a] = struct{}{}
+	}
+
+	for _, a := range assignments {
+		if _, ok := present[a]; !ok {
+			continue
+		}
+		if err := c.unassign(ctx, user, tenant, a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *userRolesClient) assign(ctx context.Context, user string, tenant string, a assignment) error {
+	var err error
+	if a.ResourceInstance == "" {
+		_, err = c.client.Api.Users.AssignRole(ctx, user, a.Role, tenant)
+	} else {
+		_, err = c.client.Api.Users.AssignResourceRole(ctx, user, a.Role, tenant, a.ResourceInstance)
+	}
+	common.AssignmentIndexFor(c.client).Invalidate(tenant, user)
+	return err
+}
+
+func (c *userRolesClient) unassign(ctx context.Context, user string, tenant string, a assignment) error {
+	var err error
+	if a.ResourceInstance == "" {
+		_, err = c.client.Api.Users.UnassignRole(ctx, user, a.Role, tenant)
+	} else {
+		_, err = c.client.Api.Users.UnassignResourceRole(ctx, user, a.Role, tenant, a.ResourceInstance)
+	}
+	common.AssignmentIndexFor(c.client).Invalidate(tenant, user)
+	return err
+}
@@ -0,0 +1,169 @@
+package user_roles
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/permit-golang/pkg/models"
+)
+
+type UserRolesModel struct {
+	Id                    types.String `tfsdk:"id"`
+	User                  types.String `tfsdk:"user"`
+	Tenant                types.String `tfsdk:"tenant"`
+	Roles                 types.Set    `tfsdk:"roles"`
+	ResourceInstanceRoles types.Set    `tfsdk:"resource_instance_roles"`
+
+	WaitForPDP types.Bool     `tfsdk:"wait_for_pdp"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+type ResourceInstanceRoleModel struct {
+	Role             types.String `tfsdk:"role"`
+	Resource         types.String `tfsdk:"resource"`
+	ResourceInstance types.String `tfsdk:"resource_instance"`
+}
+
+var resourceInstanceRoleType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"role":              types.StringType,
+		"resource":          types.StringType,
+		"resource_instance": types.StringType,
+	},
+}
+
+// assignment is a single role granted to the user in the tenant. Tenant roles
+// have an empty resource instance, which is otherwise "resource:instance".
+type assignment struct {
+	Role             string
+	ResourceInstance string
+}
+
+func (a assignment) String() string {
+	if a.ResourceInstance == "" {
+		return a.Role
+	}
+	return fmt.Sprintf("%s on %s", a.Role, a.ResourceInstance)
+}
+
+func assignmentFromRead(read models.RoleAssignmentRead) assignment {
+	if read.ResourceInstance == nil {
+		return assignment{Role: read.Role}
+	}
+	return assignment{Role: read.Role, ResourceInstance: *read.ResourceInstance}
+}
+
+// assignmentsFromModel returns the assignments described by the roles and
+// resource_instance_roles attributes.
+func assignmentsFromModel(roles []string, instanceRoles []ResourceInstanceRoleModel) []assignment {
+	var assignments []assignment
+
+	for _, role := range roles {
+		assignments = append(assignments, assignment{Role: role})
+	}
+
+	for _, instanceRole := range instanceRoles {
+		assignments = append(assignments, assignment{
+			Role:             instanceRole.Role.ValueString(),
+			ResourceInstance: fmt.Sprintf("%s:%s", instanceRole.Resource.ValueString(), instanceRole.ResourceInstance.ValueString()),
+		})
+	}
+
+	return assignments
+}
+
+// splitAssignments turns assignments back into the values of the roles and
+// resource_instance_roles attributes.
+func splitAssignments(assignments []assignment) ([]string, []ResourceInstanceRoleModel) {
+	roles := []string{}
+	instanceRoles := []ResourceInstanceRoleModel{}
+
+	for _, a := range assignments {
+		if a.ResourceInstance == "" {
+			roles = append(roles, a.Role)
+			continue
+		}
+
+		resource, instance, _ := strings.Cut(a.ResourceInstance, ":")
+		instanceRoles = append(instanceRoles, ResourceInstanceRoleModel{
+			Role:             types.StringValue(a.Role),
+			Resource:         types.StringValue(resource),
+			ResourceInstance: types.StringValue(instance),
+		})
+	}
+
+	return roles, instanceRoles
+}
+
+// diffAssignments returns the assignments in desired that are missing from
+// current, and the ones in current that are not desired.
+func diffAssignments(desired []assignment, current []assignment) (toAssign []assignment, toUnassign []assignment) {
+	desiredSet := make(map[assignment]struct{}, len(desired))
+	for _, a := range desired {
+		desiredSet[a] = struct{}{}
+	}
+
+	currentSet := make(map[assignment]struct{}, len(current))
+	for _, a := range current {
+		currentSet[a] = struct{}{}
+	}
+
+	for a := range desiredSet {
+		if _, ok := currentSet[a]; !ok {
+			toAssign = append(toAssign, a)
+		}
+	}
+
+	for a := range currentSet {
+		if _, ok := desiredSet[a]; !ok {
+			toUnassign = append(toUnassign, a)
+		}
+	}
+
+	sortAssignments(toAssign)
+	sortAssignments(toUnassign)
+
+	return toAssign, toUnassign
+}
+
+// rolesByScope groups the roles of assignments by their resource instance,
+// with tenant roles under the empty string.
+func rolesByScope(assignments []assignment) map[string][]string {
+	scopes := make(map[string][]string)
+	for _, a := range assignments {
+		scopes[a.ResourceInstance] = append(scopes[a.ResourceInstance], a.Role)
+	}
+	return scopes
+}
+
+// pdpReflects reports whether the roles reported by the PDP include every
+// granted role and none of the removed ones.
+func pdpReflects(reported []string, granted []string, removed []string) bool {
+	for _, role := range granted {
+		if !slices.Contains(reported, role) {
+			return false
+		}
+	}
+
+	for _, role := range removed {
+		if slices.Contains(reported, role) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortAssignments(assignments []assignment) {
+	sort.Slice(assignments, func(i, j int) bool {
+		if assignments[i].ResourceInstance != assignments[j].ResourceInstance {
+			return assignments[i].ResourceInstance < assignments[j].ResourceInstance
+		}
+		return assignments[i].Role < assignments[j].Role
+	})
+}
@@ -0,0 +1,110 @@
+package user_roles
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDiffAssignments(t *testing.T) {
+	viewer := assignment{Role: "viewer"}
+	admin := assignment{Role: "admin"}
+	docEditor := assignment{Role: "editor", ResourceInstance: "document:doc-1"}
+
+	tests := []struct {
+		name         string
+		desired      []assignment
+		current      []assignment
+		wantAssign   []assignment
+		wantUnassign []assignment
+	}{
+		{"in sync", []assignment{viewer, docEditor}, []assignment{docEditor, viewer}, nil, nil},
+		{"missing", []assignment{viewer, docEditor}, []assignment{viewer}, []assignment{docEditor}, nil},
+		{"unmanaged", []assignment{viewer}, []assignment{viewer, admin, docEditor}, nil, []assignment{admin, docEditor}},
+		{"empty desired", nil, []assignment{admin}, nil, []assignment{admin}},
+		{"duplicates", []assignment{viewer, viewer}, nil, []assignment{viewer}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toAssign, toUnassign := diffAssignments(tt.desired, tt.current)
+			if !reflect.DeepEqual(toAssign, tt.wantAssign) {
+				t.Errorf("toAssign = %v, want %v", toAssign, tt.wantAssign)
+			}
+			if !reflect.DeepEqual(toUnassign, tt.wantUnassign) {
+				t.Errorf("toUnassign = %v, want %v", toUnassign, tt.wantUnassign)
+			}
+		})
+	}
+}
+
+func TestRolesByScope(t *testing.T) {
+	got := rolesByScope([]assignment{
+		{Role: "viewer"},
+		{Role: "editor", ResourceInstance: "document:doc-1"},
+		{Role: "admin"},
+		{Role: "owner", ResourceInstance: "document:doc-1"},
+	})
+
+	want := map[string][]string{
+		"":               {"viewer", "admin"},
+		"document:doc-1": {"editor", "owner"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rolesByScope() = %v, want %v", got, want)
+	}
+}
+
+func TestPDPReflects(t *testing.T) {
+	tests := []struct {
+		name     string
+		reported []string
+		granted  []string
+		removed  []string
+		want     bool
+	}{
+		{"granted", []string{"viewer", "editor"}, []string{"editor"}, nil, true},
+		{"not granted yet", []string{"viewer"}, []string{"editor"}, nil, false},
+		{"removed", []string{"viewer"}, []string{"viewer"}, []string{"admin"}, true},
+		{"not removed yet", []string{"viewer", "admin"}, []string{"viewer"}, []string{"admin"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := pdpReflects(tt.reported, tt.granted, tt.removed); got != tt.want {
+			t.Errorf("%s: pdpReflects() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssignmentsRoundTrip(t *testing.T) {
+	roles := []string{"viewer"}
+	instanceRoles := []ResourceInstanceRoleModel{{
+		Role:             types.StringValue("editor"),
+		Resource:         types.StringValue("document"),
+		ResourceInstance: types.StringValue("doc-1"),
+	}}
+
+	assignments := assignmentsFromModel(roles, instanceRoles)
+	want := []assignment{{Role: "viewer"}, {Role: "editor", ResourceInstance: "document:doc-1"}}
+	if !reflect.DeepEqual(assignments, want) {
+		t.Fatalf("assignmentsFromModel() = %v, want %v", assignments, want)
+	}
+
+	gotRoles, gotInstanceRoles := splitAssignments(assignments)
+	if !reflect.DeepEqual(gotRoles, roles) {
+		t.Errorf("roles = %v, want %v", gotRoles, roles)
+	}
+	if !reflect.DeepEqual(gotInstanceRoles, instanceRoles) {
+		t.Errorf("resource instance roles = %v, want %v", gotInstanceRoles, instanceRoles)
+	}
+}
+
+func TestAssignmentString(t *testing.T) {
+	if got := (assignment{Role: "viewer"}).String(); got != "viewer" {
+		t.Errorf("String() = %q, want %q", got, "viewer")
+	}
+	if got := (assignment{Role: "editor", ResourceInstance: "document:doc-1"}).String(); got != "editor on document:doc-1" {
+		t.Errorf("String() = %q, want %q", got, "editor on document:doc-1")
+	}
+}
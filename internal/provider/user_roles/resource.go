@@ -0,0 +1,360 @@
+package user_roles
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
+)
+
+var (
+	_ resource.Resource                = &UserRolesResource{}
+	_ resource.ResourceWithConfigure   = &UserRolesResource{}
+	_ resource.ResourceWithImportState = &UserRolesResource{}
+)
+
+func NewUserRolesResource() resource.Resource {
+	return &UserRolesResource{}
+}
+
+type UserRolesResource struct {
+	client userRolesClient
+}
+
+func (r *UserRolesResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	permitClient := common.Configure(ctx, request, response)
+	r.client = userRolesClient{client: permitClient}
+}
+
+func (r *UserRolesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_roles"
+}
+
+func (r *UserRolesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Authoritatively manages every role a user has within a tenant, both tenant-level and resource instance roles. " +
+			"Roles granted outside this resource, for example in the Permit UI or by your application, are reported as drift and removed on apply. " +
+			"Do not combine it with `permitio_role_assignment` or `permitio_resource_instance_role_assignment` for the same user and tenant.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier in the format `user:tenant`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "User key whose roles are managed",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: common.FreeFormKeyValidators(),
+			},
+			"tenant": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Tenant key the roles are scoped to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: common.KeyFormatValidators(),
+			},
+			"roles": schema.SetAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				MarkdownDescription: "The complete set of tenant-level role keys the user has in the tenant",
+				Validators:          []validator.Set{setvalidator.ValueStringsAre(common.KeyFormatValidators()...)},
+			},
+			"resource_instance_roles": schema.SetNestedAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(resourceInstanceRoleType, []attr.Value{})),
+				MarkdownDescription: "The complete set of resource instance roles the user has in the tenant",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"role": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Resource role key",
+							Validators:          common.KeyFormatValidators(),
+						},
+						"resource": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Resource type (e.g., 'workspace', 'document')",
+							Validators:          common.KeyFormatValidators(),
+						},
+						"resource_instance": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Resource instance key (e.g., 'ws-123', 'doc-456')",
+							Validators:          common.FreeFormKeyValidators(),
+						},
+					},
+				},
+			},
+			"wait_for_pdp": common.WaitForPDPAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": common.TimeoutsBlock(),
+		},
+	}
+}
+
+func (r *UserRolesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan UserRolesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+
+	r.apply(ctx, plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *UserRolesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data UserRolesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, data.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+
+	current, err := r.client.List(ctx, data.User.ValueString(), data.Tenant.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read user roles",
+			fmt.Sprintf("Unable to list roles of user %s in tenant %s: %s", data.User.ValueString(), data.Tenant.ValueString(), err),
+		)
+		return
+	}
+
+	// Imported resources have no managed roles yet, so everything found is
+	// simply taken into state.
+	if !data.Roles.IsNull() {
+		managed, diags := assignmentsFromState(ctx, data)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if _, unmanaged := diffAssignments(managed, current); len(unmanaged) > 0 {
+			descriptions := make([]string, len(unmanaged))
+			for i, a := range unmanaged {
+				descriptions[i] = a.String()
+			}
+			resp.Diagnostics.AddWarning(
+				"Unmanaged role assignments found",
+				fmt.Sprintf("User %s has roles in tenant %s that are not in the configuration: %s. They will be removed on the next apply.",
+					data.User.ValueString(), data.Tenant.ValueString(), strings.Join(descriptions, ", ")),
+			)
+		}
+	}
+
+	state, diags := stateFromAssignments(ctx, data.User.ValueString(), data.Tenant.ValueString(), current)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.WaitForPDP = data.WaitForPDP
+	state.Timeouts = data.Timeouts
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *UserRolesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan UserRolesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+
+	r.apply(ctx, plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *UserRolesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state UserRolesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.OperationContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+
+	managed, diags := assignmentsFromState(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.Unassign(ctx, state.User.ValueString(), state.Tenant.ValueString(), managed); err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting user roles",
+			fmt.Sprintf("Could not unassign roles from user %s in tenant %s: %s", state.User.ValueString(), state.Tenant.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	awaitPDP(ctx, state.WaitForPDP, state.User.ValueString(), state.Tenant.ValueString(), nil, managed, &resp.Diagnostics)
+}
+
+func (r *UserRolesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Format: user:tenant
+	separator := strings.LastIndex(req.ID, ":")
+	if separator <= 0 || separator == len(req.ID)-1 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID format",
+			"Expected format: user:tenant\n\n"+
+				"Example: terraform import permitio_user_roles.example \"user@example.com:default\"",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user"), req.ID[:separator])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), req.ID[separator+1:])...)
+}
+
+// apply reconciles the user's roles with the plan and stores the result.
+func (r *UserRolesResource) apply(ctx context.Context, plan UserRolesModel, state *tfsdk.State, diags *diag.Diagnostics) {
+	desired, d := assignmentsFromState(ctx, plan)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	user := plan.User.ValueString()
+	tenant := plan.Tenant.ValueString()
+
+	removed, err := r.client.Reconcile(ctx, user, tenant, desired)
+	if err != nil {
+		diags.AddError(
+			"Unable to set user roles",
+			fmt.Sprintf("Unable to set roles of user %s in tenant %s: %s", user, tenant, err),
+		)
+		return
+	}
+
+	// The roles are stored as listed below, so they are waited for first for
+	// the state to match the plan.
+	common.AwaitWrite(ctx, "user roles", func(ctx context.Context) (bool, error) {
+		listed, err := r.client.List(ctx, user, tenant)
+		toAssign, toUnassign := diffAssignments(desired, listed)
+		return err == nil && len(toAssign) == 0 && len(toUnassign) == 0, err
+	}, diags)
+	if diags.HasError() {
+		return
+	}
+
+	current, err := r.client.List(ctx, user, tenant)
+	if err != nil {
+		diags.AddError(
+			"Unable to read user roles",
+			fmt.Sprintf("Unable to list roles of user %s in tenant %s: %s", user, tenant, err),
+		)
+		return
+	}
+
+	result, d := stateFromAssignments(ctx, user, tenant, current)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+	result.WaitForPDP = plan.WaitForPDP
+	result.Timeouts = plan.Timeouts
+
+	diags.Append(state.Set(ctx, &result)...)
+	if diags.HasError() {
+		return
+	}
+
+	awaitPDP(ctx, plan.WaitForPDP, user, tenant, desired, removed, diags)
+}
+
+// awaitPDP waits for the PDP to grant the user every role in granted and none
+// of the roles in removed. Roles granted through derivations are also reported
+// by the PDP, so other roles it reports are ignored, and a removed role that is
+// still derived keeps the wait going until it times out.
+func awaitPDP(ctx context.Context, waitForPDP types.Bool, user string, tenant string, granted []assignment, removed []assignment, diags *diag.Diagnostics) {
+	grantedByScope := rolesByScope(granted)
+	removedByScope := rolesByScope(removed)
+
+	scopes := make(map[string]struct{}, len(grantedByScope)+len(removedByScope))
+	for scope := range grantedByScope {
+		scopes[scope] = struct{}{}
+	}
+	for scope := range removedByScope {
+		scopes[scope] = struct{}{}
+	}
+
+	common.AwaitPDP(ctx, "user roles", waitForPDP, func(ctx context.Context, pdp *common.PDPClient) (bool, error) {
+		for scope := range scopes {
+			reported, err := pdp.UserRoles(ctx, user, tenant, scope)
+			if err != nil {
+				return false, err
+			}
+
+			if !pdpReflects(reported, grantedByScope[scope], removedByScope[scope]) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, diags)
+}
+
+func assignmentsFromState(ctx context.Context, m UserRolesModel) ([]assignment, diag.Diagnostics) {
+	var (
+		diags         diag.Diagnostics
+		roles         []string
+		instanceRoles []ResourceInstanceRoleModel
+	)
+
+	if !m.Roles.IsNull() && !m.Roles.IsUnknown() {
+		diags.Append(m.Roles.ElementsAs(ctx, &roles, false)...)
+	}
+
+	if !m.ResourceInstanceRoles.IsNull() && !m.ResourceInstanceRoles.IsUnknown() {
+		diags.Append(m.ResourceInstanceRoles.ElementsAs(ctx, &instanceRoles, false)...)
+	}
+
+	return assignmentsFromModel(roles, instanceRoles), diags
+}
+
+func stateFromAssignments(ctx context.Context, user string, tenant string, assignments []assignment) (UserRolesModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	roles, instanceRoles := splitAssignments(assignments)
+
+	rolesValue, d := types.SetValueFrom(ctx, types.StringType, roles)
+	diags.Append(d...)
+
+	instanceRolesValue, d := types.SetValueFrom(ctx, resourceInstanceRoleType, instanceRoles)
+	diags.Append(d...)
+
+	return UserRolesModel{
+		Id:                    types.StringValue(fmt.Sprintf("%s:%s", user, tenant)),
+		User:                  types.StringValue(user),
+		Tenant:                types.StringValue(tenant),
+		Roles:                 rolesValue,
+		ResourceInstanceRoles: instanceRolesValue,
+	}, diags
+}
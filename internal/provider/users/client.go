@@ -14,5 +14,5 @@ func (c *userClient) Read(ctx context.Context, key string) (userModel, error) {
 	if err != nil {
 		return userModel{}, err
 	}
-	return tfModelFromUserRead(*userRead), nil
+	return tfModelFromUserRead(*userRead)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 var (
@@ -51,6 +52,7 @@ func (d *UserDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 			"key": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "User key identifier",
+				Validators:          common.FreeFormKeyValidators(),
 			},
 			"email": schema.StringAttribute{
 				Computed:            true,
@@ -64,9 +66,13 @@ func (d *UserDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				Computed:            true,
 				MarkdownDescription: "User's last name",
 			},
-			"attributes": schema.StringAttribute{
+			"attributes": schema.DynamicAttribute{
 				Computed:            true,
-				MarkdownDescription: "Custom user attributes as JSON string",
+				MarkdownDescription: "Custom user attributes as an object",
+			},
+			"attributes_json": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Custom user attributes as JSON string. Kept for configurations written before `attributes` became an object",
 			},
 			"organization_id": schema.StringAttribute{
 				Computed:            true,
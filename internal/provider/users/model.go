@@ -4,21 +4,23 @@ import (
 	"encoding/json"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/permitio/permit-golang/pkg/models"
+	"github.com/permitio/terraform-provider-permit-io/internal/provider/common"
 )
 
 type userModel struct {
-	Id             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	ProjectId      types.String `tfsdk:"project_id"`
-	EnvironmentId  types.String `tfsdk:"environment_id"`
-	Key            types.String `tfsdk:"key"`
-	Email          types.String `tfsdk:"email"`
-	FirstName      types.String `tfsdk:"first_name"`
-	LastName       types.String `tfsdk:"last_name"`
-	Attributes     types.String `tfsdk:"attributes"`
+	Id             types.String  `tfsdk:"id"`
+	OrganizationId types.String  `tfsdk:"organization_id"`
+	ProjectId      types.String  `tfsdk:"project_id"`
+	EnvironmentId  types.String  `tfsdk:"environment_id"`
+	Key            types.String  `tfsdk:"key"`
+	Email          types.String  `tfsdk:"email"`
+	FirstName      types.String  `tfsdk:"first_name"`
+	LastName       types.String  `tfsdk:"last_name"`
+	Attributes     types.Dynamic `tfsdk:"attributes"`
+	AttributesJson types.String  `tfsdk:"attributes_json"`
 }
 
-func tfModelFromUserRead(m models.UserRead) userModel {
+func tfModelFromUserRead(m models.UserRead) (userModel, error) {
 	r := userModel{}
 	r.Id = types.StringValue(m.Id)
 	r.Key = types.StringValue(m.Key)
@@ -29,16 +31,22 @@ func tfModelFromUserRead(m models.UserRead) userModel {
 	r.ProjectId = types.StringValue(m.ProjectId)
 	r.OrganizationId = types.StringValue(m.OrganizationId)
 
+	attributes, err := common.AttributesToDynamic(m.Attributes)
+	if err != nil {
+		return userModel{}, err
+	}
+	r.Attributes = attributes
+
 	if len(m.Attributes) > 0 {
 		attributesJSON, err := json.Marshal(m.Attributes)
 		if err == nil {
-			r.Attributes = types.StringValue(string(attributesJSON))
+			r.AttributesJson = types.StringValue(string(attributesJSON))
 		} else {
-			r.Attributes = types.StringValue("{}")
+			r.AttributesJson = types.StringValue("{}")
 		}
 	} else {
-		r.Attributes = types.StringNull()
+		r.AttributesJson = types.StringNull()
 	}
 
-	return r
+	return r, nil
 }
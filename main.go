@@ -5,10 +5,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"io"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	permitConfig "github.com/permitio/permit-golang/pkg/config"
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/permitio/terraform-provider-permit-io/internal/export"
 	"github.com/permitio/terraform-provider-permit-io/internal/provider"
 )
 
@@ -31,12 +37,24 @@ var (
 	// https://goreleaser.com/cookbooks/using-main.version/
 )
 
+var errMissingApiKey = errors.New("PERMITIO_API_KEY must be set to export an environment")
+
 func main() {
-	var debug bool
+	var debug, exportMode bool
+	var output string
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&exportMode, "export", false, "write Terraform configuration with import blocks for the environment of PERMITIO_API_KEY instead of serving the provider")
+	flag.StringVar(&output, "output", "-", "file the -export configuration is written to, or - for stdout")
 	flag.Parse()
 
+	if exportMode {
+		if err := runExport(context.Background(), output); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	opts := providerserver.ServeOpts{
 		Address: "registry.terraform.io/permitio/permit-io",
 		Debug:   debug,
@@ -48,3 +66,33 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// runExport generates configuration for the environment the API key belongs
+// to. The key and API URL are taken from the same environment variables the
+// provider reads.
+func runExport(ctx context.Context, output string) error {
+	apiKey, ok := os.LookupEnv("PERMITIO_API_KEY")
+	if !ok {
+		return errMissingApiKey
+	}
+
+	apiUrl, ok := os.LookupEnv("PERMITIO_API_URL")
+	if !ok {
+		apiUrl = provider.DefaultApiUrl
+	}
+
+	clientConfig := permitConfig.NewConfigBuilder(apiKey).WithApiUrl(apiUrl).WithTimeout(provider.DefaultTimeout).Build()
+	permitClient := permit.NewPermit(clientConfig)
+
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return export.New(permitClient).Export(ctx, w)
+}